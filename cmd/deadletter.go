@@ -0,0 +1,191 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/0xPolygonHermez/zkevm-pool-manager/config"
+	"github.com/0xPolygonHermez/zkevm-pool-manager/db"
+	"github.com/0xPolygonHermez/zkevm-pool-manager/types"
+	"github.com/urfave/cli/v2"
+)
+
+var (
+	txIDFlag = cli.Uint64SliceFlag{
+		Name:  "tx-id",
+		Usage: "Select the dead-lettered tx with `ID` (can be repeated)",
+	}
+	categoryFlag = cli.StringSliceFlag{
+		Name:  "category",
+		Usage: "Select the dead-lettered txs with error `CATEGORY` (can be repeated): nonce, funds, gas, rejected, reverted, expired, send_limit",
+	}
+	fromFlag = cli.TimestampFlag{
+		Name:   "from",
+		Usage:  "Select the txs dead-lettered at or after `TIME` (RFC3339)",
+		Layout: time.RFC3339,
+	}
+	toFlag = cli.TimestampFlag{
+		Name:   "to",
+		Usage:  "Select the txs dead-lettered before `TIME` (RFC3339)",
+		Layout: time.RFC3339,
+	}
+	limitFlag = cli.Uint64Flag{
+		Name:  "limit",
+		Usage: "Maximum `NUMBER` of dead-lettered txs to select, the most recent first",
+	}
+)
+
+// deadLetterCommand returns the command to inspect, requeue and purge the dead-lettered txs
+func deadLetterCommand() *cli.Command {
+	filterFlags := []cli.Flag{&configFileFlag, &txIDFlag, &categoryFlag, &fromFlag, &toFlag, &limitFlag}
+
+	return &cli.Command{
+		Name:  "deadletter",
+		Usage: "Inspect, requeue and purge the txs in the dead-letter store",
+		Subcommands: []*cli.Command{
+			{
+				Name:   "list",
+				Usage:  "List the dead-lettered txs",
+				Action: deadLetterListCmd,
+				Flags:  filterFlags,
+			},
+			{
+				Name:      "inspect",
+				Usage:     "Show a dead-lettered tx with its error history",
+				ArgsUsage: "TX_ID",
+				Action:    deadLetterInspectCmd,
+				Flags:     []cli.Flag{&configFileFlag},
+			},
+			{
+				Name:   "requeue",
+				Usage:  "Move the dead-lettered txs back to be resent, except the reverted ones",
+				Action: deadLetterRequeueCmd,
+				Flags:  filterFlags,
+			},
+			{
+				Name:   "purge",
+				Usage:  "Delete the dead-lettered txs from the pool database",
+				Action: deadLetterPurgeCmd,
+				Flags:  filterFlags,
+			},
+		},
+	}
+}
+
+func deadLetterListCmd(cliCtx *cli.Context) error {
+	poolDB, err := newDeadLetterPoolDB(cliCtx)
+	if err != nil {
+		return err
+	}
+	defer poolDB.Close()
+
+	deadLetters, err := poolDB.GetDeadLetters(cliCtx.Context, deadLetterFilter(cliCtx))
+	if err != nil {
+		return fmt.Errorf("error getting dead-lettered txs, error: %w", err)
+	}
+
+	return printJSON(deadLetters)
+}
+
+func deadLetterInspectCmd(cliCtx *cli.Context) error {
+	var txID uint64
+	if _, err := fmt.Sscan(cliCtx.Args().First(), &txID); err != nil {
+		return fmt.Errorf("invalid tx id %q", cliCtx.Args().First())
+	}
+
+	poolDB, err := newDeadLetterPoolDB(cliCtx)
+	if err != nil {
+		return err
+	}
+	defer poolDB.Close()
+
+	deadLetter, err := poolDB.GetDeadLetter(cliCtx.Context, txID)
+	if err != nil {
+		return fmt.Errorf("error getting dead-lettered tx %d, error: %w", txID, err)
+	}
+
+	return printJSON(deadLetter)
+}
+
+func deadLetterRequeueCmd(cliCtx *cli.Context) error {
+	filter := deadLetterFilter(cliCtx)
+	if filter.IsEmpty() {
+		return requiredFilterError("requeue")
+	}
+
+	poolDB, err := newDeadLetterPoolDB(cliCtx)
+	if err != nil {
+		return err
+	}
+	defer poolDB.Close()
+
+	requeued, err := poolDB.RequeueDeadLetters(cliCtx.Context, filter)
+	if err != nil {
+		return fmt.Errorf("error requeuing dead-lettered txs, error: %w", err)
+	}
+
+	fmt.Printf("%d dead-lettered txs requeued\n", requeued)
+	return nil
+}
+
+func deadLetterPurgeCmd(cliCtx *cli.Context) error {
+	filter := deadLetterFilter(cliCtx)
+	if filter.IsEmpty() {
+		return requiredFilterError("purge")
+	}
+
+	poolDB, err := newDeadLetterPoolDB(cliCtx)
+	if err != nil {
+		return err
+	}
+	defer poolDB.Close()
+
+	purged, err := poolDB.PurgeDeadLetters(cliCtx.Context, filter)
+	if err != nil {
+		return fmt.Errorf("error purging dead-lettered txs, error: %w", err)
+	}
+
+	fmt.Printf("%d dead-lettered txs purged\n", purged)
+	return nil
+}
+
+// newDeadLetterPoolDB loads the config file and connects to the pool database
+func newDeadLetterPoolDB(cliCtx *cli.Context) (*db.PoolDB, error) {
+	c, err := config.Load(cliCtx, true)
+	if err != nil {
+		return nil, err
+	}
+
+	return db.NewPoolDB(c.DB)
+}
+
+// deadLetterFilter returns the dead-letter filter set by the command flags
+func deadLetterFilter(cliCtx *cli.Context) types.DeadLetterFilter {
+	filter := types.DeadLetterFilter{
+		TxIds:      cliCtx.Uint64Slice(txIDFlag.Name),
+		Categories: cliCtx.StringSlice(categoryFlag.Name),
+		Limit:      cliCtx.Uint64(limitFlag.Name),
+	}
+	if from := cliCtx.Timestamp(fromFlag.Name); from != nil {
+		filter.From = *from
+	}
+	if to := cliCtx.Timestamp(toFlag.Name); to != nil {
+		filter.To = *to
+	}
+
+	return filter
+}
+
+// requiredFilterError returns the error of an action on the dead-lettered txs run without filter flags, to avoid
+// running it on all the dead-lettered txs by mistake
+func requiredFilterError(action string) error {
+	return fmt.Errorf("at least one of --%s, --%s, --%s or --%s is required to %s dead-lettered txs", txIDFlag.Name, categoryFlag.Name, fromFlag.Name, toFlag.Name, action)
+}
+
+func printJSON(v interface{}) error {
+	encoder := json.NewEncoder(os.Stdout)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(v)
+}
@@ -6,11 +6,13 @@ import (
 	"os"
 	"os/signal"
 	"runtime"
+	"syscall"
 
 	"github.com/0xPolygonHermez/zkevm-data-streamer"
 	version "github.com/0xPolygonHermez/zkevm-pool-manager"
 	"github.com/0xPolygonHermez/zkevm-pool-manager/config"
 	"github.com/0xPolygonHermez/zkevm-pool-manager/db"
+	"github.com/0xPolygonHermez/zkevm-pool-manager/leader"
 	"github.com/0xPolygonHermez/zkevm-pool-manager/log"
 	"github.com/0xPolygonHermez/zkevm-pool-manager/monitor"
 	"github.com/0xPolygonHermez/zkevm-pool-manager/sender"
@@ -55,6 +57,7 @@ func main() {
 			Action:  start,
 			Flags:   append(flags, &migrationsFlag),
 		},
+		deadLetterCommand(),
 	}
 
 	err := app.Run(os.Args)
@@ -88,23 +91,66 @@ func start(cliCtx *cli.Context) error {
 	}
 	checkPoolMigrations(c.DB)
 
-	var cancelFuncs []context.CancelFunc
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
 
 	poolDB, err := db.NewPoolDB(c.DB)
 	if err != nil {
 		log.Fatalf("error when creating pool DB instance, error: %v", err)
 	}
+	go poolDB.StartLeaseHeartbeat(ctx)
 
-	monitor := monitor.NewMonitor(c.Monitor, poolDB)
-	go monitor.Start()
+	elector := leader.NewElector(c.Leader, poolDB)
+	elector.Start(ctx)
 
-	sender := sender.NewSender(c.Sender, poolDB, monitor)
-	go sender.Start()
+	monitor := monitor.NewMonitor(c.Monitor, poolDB, elector)
+	go monitor.Start(ctx)
 
-	server := server.NewServer(c.Server, poolDB, sender)
+	sender := sender.NewSender(c.Sender, poolDB, monitor, elector)
+	go sender.Start(ctx)
+
+	// Fail fast if the sequencer or the L2 node are not reachable at startup
+	if err := monitor.WaitWorkersAlive(); err != nil {
+		log.Fatalf("error starting monitor, error: %v", err)
+	}
+	if err := sender.WaitWorkersAlive(); err != nil {
+		log.Fatalf("error starting sender, error: %v", err)
+	}
+
+	server := server.NewServer(c.Server, poolDB, sender, monitor, elector)
 	go server.Start()
 
-	waitSignal(cancelFuncs)
+	waitSignal()
+	log.Info("terminating application gracefully...")
+
+	shutdownCtx, shutdownCancel := context.WithTimeout(context.Background(), c.ShutdownTimeout.Duration)
+	defer shutdownCancel()
+
+	// Stop accepting new txs, then stop the background loops and wait for the txs in progress to be sent and monitored
+	if err := server.Stop(shutdownCtx); err != nil {
+		log.Errorf("error stopping server, error: %v", err)
+	}
+	cancel()
+	if err := sender.Stop(shutdownCtx); err != nil {
+		log.Errorf("error stopping sender, error: %v", err)
+	}
+	if err := monitor.Stop(shutdownCtx); err != nil {
+		log.Errorf("error stopping monitor, error: %v", err)
+	}
+	if err := elector.Stop(shutdownCtx); err != nil {
+		log.Errorf("error releasing leadership, error: %v", err)
+	}
+	if c.DB.Lease.Enabled {
+		released, err := poolDB.ReleaseL2TransactionLeases(shutdownCtx)
+		if err != nil {
+			log.Errorf("error releasing leases, error: %v", err)
+		} else {
+			log.Infof("released %d leases", released)
+		}
+	}
+	poolDB.Close()
+
+	log.Info("application stopped")
 
 	return nil
 }
@@ -129,22 +175,13 @@ func checkPoolMigrations(c db.Config) {
 	}
 }
 
-func waitSignal(cancelFuncs []context.CancelFunc) {
+// waitSignal blocks until the application receives an interrupt or a terminate signal
+func waitSignal() {
 	signals := make(chan os.Signal, 1)
-	signal.Notify(signals, os.Interrupt)
-
-	for sig := range signals {
-		switch sig {
-		case os.Interrupt, os.Kill:
-			log.Info("terminating application gracefully...")
-
-			exitStatus := 0
-			for _, cancel := range cancelFuncs {
-				cancel()
-			}
-			os.Exit(exitStatus)
-		}
-	}
+	signal.Notify(signals, os.Interrupt, syscall.SIGTERM)
+	defer signal.Stop(signals)
+
+	<-signals
 }
 
 func logVersion() {
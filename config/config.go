@@ -5,7 +5,10 @@ import (
 	"path/filepath"
 	"strings"
 
+	"github.com/0xPolygonHermez/zkevm-pool-manager/config/types"
 	"github.com/0xPolygonHermez/zkevm-pool-manager/db"
+	"github.com/0xPolygonHermez/zkevm-pool-manager/leader"
+	"github.com/0xPolygonHermez/zkevm-pool-manager/limiter"
 	"github.com/0xPolygonHermez/zkevm-pool-manager/log"
 	"github.com/0xPolygonHermez/zkevm-pool-manager/monitor"
 	"github.com/0xPolygonHermez/zkevm-pool-manager/sender"
@@ -27,6 +30,10 @@ const (
 )
 
 type Config struct {
+	// ShutdownTimeout is the max time to wait for the server, the sender and the monitor to finish the requests in
+	// progress when the application is stopped
+	ShutdownTimeout types.Duration
+
 	// Log configuration
 	Log log.Config
 
@@ -41,6 +48,9 @@ type Config struct {
 
 	// Monitor configuration
 	Monitor monitor.Config
+
+	// Leader election configuration
+	Leader leader.Config
 }
 
 // Default parses the default configuration values.
@@ -113,4 +123,88 @@ func validate(cfg *Config) {
 	if cfg.Monitor.QueueSize < cfg.Monitor.Workers {
 		log.Fatalf("invalid configuration: Monitor.QueueSize must be greater or equal than Monitor.NumberWorkers")
 	}
+	if cfg.Sender.BatchSend.Enabled && cfg.Sender.BatchSend.MaxSize == 0 {
+		log.Fatalf("invalid configuration: Sender.BatchSend.MaxSize must be greater than 0")
+	}
+	if cfg.Monitor.BatchTracking.Enabled && (cfg.Monitor.BatchTracking.CheckInterval.Duration <= 0 || cfg.Monitor.BatchTracking.MaxBlocksPerCheck == 0) {
+		log.Fatalf("invalid configuration: Monitor.BatchTracking.CheckInterval and Monitor.BatchTracking.MaxBlocksPerCheck must be greater than 0")
+	}
+	if cfg.Monitor.ReorgDetection.Enabled && (cfg.Monitor.ReorgDetection.CheckInterval.Duration <= 0 || cfg.Monitor.ReorgDetection.Depth == 0) {
+		log.Fatalf("invalid configuration: Monitor.ReorgDetection.CheckInterval and Monitor.ReorgDetection.Depth must be greater than 0")
+	}
+	if cfg.Monitor.DropDetection.Enabled && cfg.Monitor.DropDetection.CheckAfter.Duration <= 0 {
+		log.Fatalf("invalid configuration: Monitor.DropDetection.CheckAfter must be greater than 0")
+	}
+	switch cfg.Monitor.Expiry.Policy {
+	case monitor.ExpiryPolicyExpire, monitor.ExpiryPolicyResend, monitor.ExpiryPolicyCheckNonce:
+	default:
+		log.Fatalf("invalid configuration: Monitor.Expiry.Policy must be %q, %q or %q", monitor.ExpiryPolicyExpire, monitor.ExpiryPolicyResend, monitor.ExpiryPolicyCheckNonce)
+	}
+	if cfg.Monitor.Expiry.LifetimeFrom != monitor.LifetimeFromReceived && cfg.Monitor.Expiry.LifetimeFrom != monitor.LifetimeFromLastSent {
+		log.Fatalf("invalid configuration: Monitor.Expiry.LifetimeFrom must be %q or %q", monitor.LifetimeFromReceived, monitor.LifetimeFromLastSent)
+	}
+	if cfg.Monitor.Expiry.Policy == monitor.ExpiryPolicyResend && (cfg.Monitor.Expiry.MaxResends == 0 || cfg.Monitor.Expiry.LifetimeFrom != monitor.LifetimeFromLastSent) {
+		log.Fatalf("invalid configuration: Monitor.Expiry.MaxResends must be greater than 0 and Monitor.Expiry.LifetimeFrom must be %q with the %q policy", monitor.LifetimeFromLastSent, monitor.ExpiryPolicyResend)
+	}
+	if cfg.Monitor.RetrySchedule.Enabled && (cfg.Monitor.RetrySchedule.FlushInterval.Duration <= 0 || cfg.Monitor.RetrySchedule.RestoreJitter.Duration < 0) {
+		log.Fatalf("invalid configuration: Monitor.RetrySchedule.FlushInterval must be greater than 0 and Monitor.RetrySchedule.RestoreJitter must be greater or equal than 0")
+	}
+	if cfg.Monitor.BatchReceipts.Enabled && cfg.Monitor.BatchReceipts.MaxSize == 0 {
+		log.Fatalf("invalid configuration: Monitor.BatchReceipts.MaxSize must be greater than 0")
+	}
+	if cfg.Sender.Shadow.Enabled && (cfg.Sender.Shadow.SequencerURL == "" || cfg.Sender.Shadow.Workers == 0) {
+		log.Fatalf("invalid configuration: Sender.Shadow.SequencerURL must be set and Sender.Shadow.Workers must be greater than 0")
+	}
+	validateRateLimit("Sender", cfg.Sender.RateLimit)
+	validateRateLimit("Monitor", cfg.Monitor.RateLimit)
+	if cfg.Monitor.BlockTracking.Enabled && (cfg.Monitor.BlockTracking.PollInterval.Duration <= 0 || cfg.Monitor.BlockTracking.StragglerWaitInterval.Duration <= 0) {
+		log.Fatalf("invalid configuration: Monitor.BlockTracking.PollInterval and Monitor.BlockTracking.StragglerWaitInterval must be greater than 0")
+	}
+	if cfg.Monitor.DataStream.Enabled {
+		if cfg.Monitor.DataStream.Server == "" || cfg.Monitor.DataStream.StragglerWaitInterval.Duration <= 0 {
+			log.Fatalf("invalid configuration: Monitor.DataStream.Server must be set and Monitor.DataStream.StragglerWaitInterval must be greater than 0")
+		}
+		if cfg.Monitor.BlockTracking.Enabled {
+			log.Fatalf("invalid configuration: Monitor.DataStream and Monitor.BlockTracking can't be enabled at the same time")
+		}
+	}
+	if cfg.Server.AdminEnabled && (cfg.Server.AdminPort <= 0 || cfg.Server.AdminPort == cfg.Server.Port) {
+		log.Fatalf("invalid configuration: Server.AdminPort must be greater than 0 and different from Server.Port")
+	}
+	if cfg.DB.Lease.Enabled {
+		if cfg.DB.Lease.HeartbeatInterval.Duration <= 0 || cfg.DB.Lease.HeartbeatInterval.Duration >= cfg.DB.Lease.Duration.Duration {
+			log.Fatalf("invalid configuration: DB.Lease.HeartbeatInterval must be greater than 0 and lower than DB.Lease.Duration")
+		}
+		if cfg.Monitor.SentTxsCheckInterval.Duration <= 0 {
+			log.Fatalf("invalid configuration: Monitor.SentTxsCheckInterval must be greater than 0 when DB.Lease is enabled, to take over the sent txs of the stopped instances")
+		}
+		if cfg.Monitor.DataStream.Enabled && cfg.DB.Lease.OwnerID == "" {
+			log.Fatalf("invalid configuration: DB.Lease.OwnerID must be set when DB.Lease and Monitor.DataStream are enabled, to resume streaming from the checkpoint of the instance after a restart")
+		}
+	}
+	if cfg.Leader.Enabled {
+		if cfg.Leader.CheckInterval.Duration <= 0 {
+			log.Fatalf("invalid configuration: Leader.CheckInterval must be greater than 0")
+		}
+		if cfg.Monitor.ExpiredTxsCheckInterval.Duration <= 0 {
+			log.Fatalf("invalid configuration: Monitor.ExpiredTxsCheckInterval must be greater than 0 when Leader is enabled, to expire the txs monitored by the followers")
+		}
+	}
+}
+
+func validateRateLimit(section string, cfg limiter.Config) {
+	if cfg.RequestsPerSecond < 0 {
+		log.Fatalf("invalid configuration: %s.RateLimit.RequestsPerSecond must be greater or equal than 0", section)
+	}
+	if cfg.Adaptive.Enabled {
+		if cfg.Adaptive.MaxConcurrency == 0 || cfg.Adaptive.MinConcurrency > cfg.Adaptive.MaxConcurrency {
+			log.Fatalf("invalid configuration: %s.RateLimit.Adaptive.MaxConcurrency must be greater than 0 and greater or equal than MinConcurrency", section)
+		}
+		if cfg.Adaptive.Window == 0 {
+			log.Fatalf("invalid configuration: %s.RateLimit.Adaptive.Window must be greater than 0", section)
+		}
+		if cfg.Adaptive.DecreaseFactor <= 0 || cfg.Adaptive.DecreaseFactor >= 1 {
+			log.Fatalf("invalid configuration: %s.RateLimit.Adaptive.DecreaseFactor must be between 0 and 1", section)
+		}
+	}
 }
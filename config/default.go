@@ -2,6 +2,8 @@ package config
 
 // DefaultValues is the default configuration
 const DefaultValues = `
+ShutdownTimeout = "30s"
+
 [Log]
 Environment = "development" # "production" or "development"
 Level = "info"
@@ -16,8 +18,11 @@ MaxRequestsPerIPAndSecond = 500
 EnableHttpLog = true
 BatchRequestsEnabled = false
 BatchRequestsLimit = 20
+AdminEnabled = false
+AdminHost = "127.0.0.1"
+AdminPort = 8547
 
-[Pool]
+[DB]
 User = "pool_user"
 Password = "pool_password"
 Name = "pool_db"
@@ -25,13 +30,70 @@ Host = "zkevm-pool-db"
 Port = "5432"
 EnableLog = false
 MaxConns = 200
+	[DB.Lease]
+	Enabled = false
+	OwnerID = ""
+	Duration = "30s"
+	HeartbeatInterval = "10s"
+
+[Leader]
+Enabled = false
+LockKey = 7919
+CheckInterval = "5s"
 
 [Sender]
 SequencerURL = "http://localhost:8467"
 ResendTxsCheckInterval = "5s"
+NotificationsEnabled = false
+PendingTxsCheckInterval = "1m"
+PendingTxsMinAge = "1m"
 Workers = 5
 QueueSize = 25
 RPCReadTimeout = "3s"
+MaxSendAttempts = 10
+MaxResendTime = "1h"
+	[Sender.SequencerAuth]
+	Headers = {}
+	BearerToken = ""
+	BearerTokenFile = ""
+	JWTSecretFile = ""
+	TokenRefreshInterval = "30s"
+	[Sender.Supervisor]
+	RestartInitialBackoff = "1s"
+	RestartMaxBackoff = "1m"
+	StartupTimeout = "1m"
+	[Sender.FairQueue]
+	Enabled = false
+	KeyType = "ip"
+	DefaultWeight = 1
+	Weights = []
+	[Sender.BatchSend]
+	Enabled = false
+	MaxSize = 20
+	MaxLinger = "10ms"
+	[Sender.Shadow]
+	Enabled = false
+	SequencerURL = ""
+	Workers = 2
+	QueueSize = 100
+	RPCReadTimeout = "3s"
+		[Sender.Shadow.SequencerAuth]
+		Headers = {}
+		BearerToken = ""
+		BearerTokenFile = ""
+		JWTSecretFile = ""
+		TokenRefreshInterval = "30s"
+	[Sender.RateLimit]
+	RequestsPerSecond = 0
+	Burst = 10
+		[Sender.RateLimit.Adaptive]
+		Enabled = false
+		MinConcurrency = 1
+		MaxConcurrency = 5
+		Window = 20
+		ErrorRateThreshold = 0.2
+		LatencyThreshold = "1s"
+		DecreaseFactor = 0.5
 
 [Monitor]
 L2NodeURL = "http://localhost:8467"
@@ -40,5 +102,60 @@ QueueSize = 25
 RetryWaitInterval = "3s"
 InitialWaitInterval = "3s"
 TxLifeTimeMax = "30m"
+SentTxsCheckInterval = "1m"
+ExpiredTxsCheckInterval = "1m"
 RPCReadTimeout = "3s"
+	[Monitor.Supervisor]
+	RestartInitialBackoff = "1s"
+	RestartMaxBackoff = "1m"
+	StartupTimeout = "1m"
+	[Monitor.L2NodeAuth]
+	Headers = {}
+	BearerToken = ""
+	BearerTokenFile = ""
+	JWTSecretFile = ""
+	TokenRefreshInterval = "30s"
+	[Monitor.RateLimit]
+	RequestsPerSecond = 0
+	Burst = 10
+		[Monitor.RateLimit.Adaptive]
+		Enabled = false
+		MinConcurrency = 1
+		MaxConcurrency = 5
+		Window = 20
+		ErrorRateThreshold = 0.2
+		LatencyThreshold = "1s"
+		DecreaseFactor = 0.5
+	[Monitor.BlockTracking]
+	Enabled = false
+	PollInterval = "1s"
+	StragglerWaitInterval = "1m"
+	[Monitor.BatchReceipts]
+	Enabled = false
+	MaxSize = 50
+	MaxLinger = "10ms"
+	[Monitor.BatchTracking]
+	Enabled = false
+	CheckInterval = "30s"
+	MaxBlocksPerCheck = 500
+	[Monitor.ReorgDetection]
+	Enabled = false
+	Depth = 100
+	CheckInterval = "10s"
+	Resend = false
+	[Monitor.DataStream]
+	Enabled = false
+	Server = ""
+	StragglerWaitInterval = "1m"
+	[Monitor.DropDetection]
+	Enabled = false
+	CheckAfter = "2m"
+	[Monitor.Expiry]
+	Policy = "expire"
+	MaxResends = 3
+	LifetimeFrom = "received"
+	[Monitor.RetrySchedule]
+	Enabled = false
+	FlushInterval = "5s"
+	RestoreJitter = "30s"
 `
@@ -0,0 +1,179 @@
+package datastream
+
+import (
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"time"
+
+	"github.com/0xPolygonHermez/zkevm-data-streamer/datastreamer"
+)
+
+const (
+	headerSize = 38
+)
+
+// ErrBookmarkNotFound is returned when the bookmark to start streaming from doesn't exist in the data stream
+var ErrBookmarkNotFound = errors.New("bookmark not found")
+
+// Client is a client of a zkEVM data stream server. Unlike datastreamer.StreamClient, it doesn't reconnect on its own
+// and the streamed entries are read by the caller, so the reads can be cancelled with a context. The client can't be
+// used anymore once a call is cancelled
+type Client struct {
+	streamType datastreamer.StreamType
+	conn       net.Conn
+}
+
+// Dial connects to the data stream server
+func Dial(ctx context.Context, server string, streamType datastreamer.StreamType) (*Client, error) {
+	dialer := &net.Dialer{}
+	conn, err := dialer.DialContext(ctx, "tcp", server)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Client{
+		streamType: streamType,
+		conn:       conn,
+	}, nil
+}
+
+// Close closes the connection to the data stream server
+func (c *Client) Close() error {
+	return c.conn.Close()
+}
+
+// withContext runs the call on the connection, interrupting it if the context is done
+func (c *Client) withContext(ctx context.Context, call func() error) error {
+	stop := context.AfterFunc(ctx, func() {
+		// Setting a deadline in the past unblocks the reads and writes in progress
+		_ = c.conn.SetDeadline(time.Unix(1, 0))
+	})
+	defer stop()
+
+	err := call()
+	if err != nil && ctx.Err() != nil {
+		return ctx.Err()
+	}
+	return err
+}
+
+// TotalEntries returns the number of entries of the data stream. It can't be called once the streaming is started
+func (c *Client) TotalEntries(ctx context.Context) (uint64, error) {
+	header := make([]byte, headerSize)
+	err := c.withContext(ctx, func() error {
+		if err := c.execCommand(datastreamer.CmdHeader, nil); err != nil {
+			return err
+		}
+		_, err := io.ReadFull(c.conn, header)
+		return err
+	})
+	if err != nil {
+		return 0, err
+	}
+	if header[0] != datastreamer.PtHeader {
+		return 0, fmt.Errorf("unexpected packet type %d, expected header", header[0])
+	}
+
+	return binary.BigEndian.Uint64(header[30:38]), nil
+}
+
+// StartFromEntry starts streaming the entries of the data stream from the entry number
+func (c *Client) StartFromEntry(ctx context.Context, entry uint64) error {
+	return c.withContext(ctx, func() error {
+		return c.execCommand(datastreamer.CmdStart, binary.BigEndian.AppendUint64(nil, entry))
+	})
+}
+
+// StartFromBookmark starts streaming the entries of the data stream from the bookmark. It returns ErrBookmarkNotFound
+// if the bookmark doesn't exist, then the server closes the connection
+func (c *Client) StartFromBookmark(ctx context.Context, bookmark []byte) error {
+	params := binary.BigEndian.AppendUint32(nil, uint32(len(bookmark)))
+	err := c.withContext(ctx, func() error {
+		return c.execCommand(datastreamer.CmdStartBookmark, append(params, bookmark...))
+	})
+
+	var cmdErr *commandError
+	if errors.As(err, &cmdErr) && cmdErr.errorNum == uint32(datastreamer.CmdErrBadFromBookmark) {
+		return ErrBookmarkNotFound
+	}
+	return err
+}
+
+// NextEntry waits for the next streamed entry
+func (c *Client) NextEntry(ctx context.Context) (*datastreamer.FileEntry, error) {
+	var packet []byte
+	err := c.withContext(ctx, func() error {
+		packet = make([]byte, datastreamer.FixedSizeFileEntry)
+		if _, err := io.ReadFull(c.conn, packet); err != nil {
+			return err
+		}
+		if packet[0] != datastreamer.PtData {
+			return fmt.Errorf("unexpected packet type %d, expected data entry", packet[0])
+		}
+
+		length := binary.BigEndian.Uint32(packet[1:5])
+		if length < datastreamer.FixedSizeFileEntry {
+			return fmt.Errorf("invalid data entry length %d", length)
+		}
+		packet = append(packet, make([]byte, length-datastreamer.FixedSizeFileEntry)...)
+		_, err := io.ReadFull(c.conn, packet[datastreamer.FixedSizeFileEntry:])
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	entry, err := datastreamer.DecodeBinaryToFileEntry(packet)
+	if err != nil {
+		return nil, err
+	}
+	return &entry, nil
+}
+
+// commandError is the error result of a command
+type commandError struct {
+	cmd      datastreamer.Command
+	errorNum uint32
+	errorStr string
+}
+
+func (e *commandError) Error() string {
+	return fmt.Sprintf("command %s failed with error %d: %s", datastreamer.StrCommand[e.cmd], e.errorNum, e.errorStr)
+}
+
+// execCommand sends the command with its params and waits for the result
+func (c *Client) execCommand(cmd datastreamer.Command, params []byte) error {
+	request := binary.BigEndian.AppendUint64(nil, uint64(cmd))
+	request = binary.BigEndian.AppendUint64(request, uint64(c.streamType))
+	request = append(request, params...)
+	if _, err := c.conn.Write(request); err != nil {
+		return err
+	}
+
+	result := make([]byte, datastreamer.FixedSizeResultEntry)
+	if _, err := io.ReadFull(c.conn, result); err != nil {
+		return err
+	}
+	if result[0] != datastreamer.PtResult {
+		return fmt.Errorf("unexpected packet type %d, expected command result", result[0])
+	}
+
+	length := binary.BigEndian.Uint32(result[1:5])
+	if length < datastreamer.FixedSizeResultEntry {
+		return fmt.Errorf("invalid command result length %d", length)
+	}
+	errorStr := make([]byte, length-datastreamer.FixedSizeResultEntry)
+	if _, err := io.ReadFull(c.conn, errorStr); err != nil {
+		return err
+	}
+
+	errorNum := binary.BigEndian.Uint32(result[5:9])
+	if errorNum != uint32(datastreamer.CmdErrOK) {
+		return &commandError{cmd: cmd, errorNum: errorNum, errorStr: string(errorStr)}
+	}
+	return nil
+}
@@ -0,0 +1,176 @@
+package datastream
+
+import (
+	"encoding/binary"
+	"fmt"
+
+	"github.com/0xPolygonHermez/zkevm-data-streamer/datastreamer"
+	"github.com/ethereum/go-ethereum/common"
+	ethTypes "github.com/ethereum/go-ethereum/core/types"
+)
+
+// StreamTypeSequencer is the type of the data stream of the sequencer of the zkEVM node
+const StreamTypeSequencer datastreamer.StreamType = 1
+
+// Entry types of the sequencer data stream
+const (
+	// EntryTypeL2BlockStart is the entry that opens a L2 block, followed by the entries of its txs
+	EntryTypeL2BlockStart datastreamer.EntryType = 1
+	// EntryTypeL2Tx is the entry of a tx of the current L2 block
+	EntryTypeL2Tx datastreamer.EntryType = 2
+	// EntryTypeL2BlockEnd is the entry that closes the current L2 block
+	EntryTypeL2BlockEnd datastreamer.EntryType = 3
+	// EntryTypeUpdateGER is the entry of an update of the global exit root
+	EntryTypeUpdateGER datastreamer.EntryType = 4
+	// EntryTypeBookmark is the entry of a bookmark
+	EntryTypeBookmark datastreamer.EntryType = datastreamer.EtBookmark
+)
+
+// Bookmark types of the sequencer data stream
+const (
+	// BookmarkTypeBatch is the type of the bookmarks of the L2 batches
+	BookmarkTypeBatch byte = 0
+	// BookmarkTypeL2Block is the type of the bookmarks of the L2 blocks
+	BookmarkTypeL2Block byte = 1
+)
+
+const (
+	bookmarkSize     = 9
+	l2BlockStartSize = 122
+	l2TxFixedSize    = 38
+	l2BlockEndSize   = 72
+)
+
+// L2BlockStart is the entry that opens a L2 block
+type L2BlockStart struct {
+	BatchNumber     uint64
+	L2BlockNumber   uint64
+	Timestamp       int64
+	DeltaTimestamp  uint32
+	L1InfoTreeIndex uint32
+	L1BlockHash     common.Hash
+	GlobalExitRoot  common.Hash
+	Coinbase        common.Address
+	ForkID          uint16
+	ChainID         uint32
+}
+
+// L2Transaction is the entry of a tx of the current L2 block
+type L2Transaction struct {
+	EffectiveGasPricePercentage uint8
+	IsValid                     uint8
+	StateRoot                   common.Hash
+	// Encoded is the tx encoded in binary format
+	Encoded []byte
+}
+
+// L2BlockEnd is the entry that closes the current L2 block
+type L2BlockEnd struct {
+	L2BlockNumber uint64
+	BlockHash     common.Hash
+	StateRoot     common.Hash
+}
+
+// L2BlockBookmark returns the bookmark of the L2 block, used to start streaming from the block
+func L2BlockBookmark(number uint64) []byte {
+	bookmark := make([]byte, bookmarkSize)
+	bookmark[0] = BookmarkTypeL2Block
+	binary.BigEndian.PutUint64(bookmark[1:], number)
+	return bookmark
+}
+
+// Encode encodes the entry to the binary format of the data stream
+func (b *L2BlockStart) Encode() []byte {
+	data := make([]byte, 0, l2BlockStartSize)
+	data = binary.BigEndian.AppendUint64(data, b.BatchNumber)
+	data = binary.BigEndian.AppendUint64(data, b.L2BlockNumber)
+	data = binary.BigEndian.AppendUint64(data, uint64(b.Timestamp))
+	data = binary.BigEndian.AppendUint32(data, b.DeltaTimestamp)
+	data = binary.BigEndian.AppendUint32(data, b.L1InfoTreeIndex)
+	data = append(data, b.L1BlockHash.Bytes()...)
+	data = append(data, b.GlobalExitRoot.Bytes()...)
+	data = append(data, b.Coinbase.Bytes()...)
+	data = binary.BigEndian.AppendUint16(data, b.ForkID)
+	data = binary.BigEndian.AppendUint32(data, b.ChainID)
+	return data
+}
+
+// DecodeL2BlockStart decodes the entry that opens a L2 block from the binary format of the data stream
+func DecodeL2BlockStart(data []byte) (*L2BlockStart, error) {
+	if len(data) != l2BlockStartSize {
+		return nil, fmt.Errorf("invalid L2 block start entry length %d, expected %d", len(data), l2BlockStartSize)
+	}
+
+	return &L2BlockStart{
+		BatchNumber:     binary.BigEndian.Uint64(data[0:8]),
+		L2BlockNumber:   binary.BigEndian.Uint64(data[8:16]),
+		Timestamp:       int64(binary.BigEndian.Uint64(data[16:24])),
+		DeltaTimestamp:  binary.BigEndian.Uint32(data[24:28]),
+		L1InfoTreeIndex: binary.BigEndian.Uint32(data[28:32]),
+		L1BlockHash:     common.BytesToHash(data[32:64]),
+		GlobalExitRoot:  common.BytesToHash(data[64:96]),
+		Coinbase:        common.BytesToAddress(data[96:116]),
+		ForkID:          binary.BigEndian.Uint16(data[116:118]),
+		ChainID:         binary.BigEndian.Uint32(data[118:122]),
+	}, nil
+}
+
+// Encode encodes the entry to the binary format of the data stream
+func (t *L2Transaction) Encode() []byte {
+	data := make([]byte, 0, l2TxFixedSize+len(t.Encoded))
+	data = append(data, t.EffectiveGasPricePercentage, t.IsValid)
+	data = append(data, t.StateRoot.Bytes()...)
+	data = binary.BigEndian.AppendUint32(data, uint32(len(t.Encoded)))
+	data = append(data, t.Encoded...)
+	return data
+}
+
+// Hash returns the hash of the encoded tx
+func (t *L2Transaction) Hash() (common.Hash, error) {
+	tx := &ethTypes.Transaction{}
+	if err := tx.UnmarshalBinary(t.Encoded); err != nil {
+		return common.Hash{}, err
+	}
+	return tx.Hash(), nil
+}
+
+// DecodeL2Transaction decodes the entry of a tx from the binary format of the data stream
+func DecodeL2Transaction(data []byte) (*L2Transaction, error) {
+	if len(data) < l2TxFixedSize {
+		return nil, fmt.Errorf("invalid L2 tx entry length %d, expected at least %d", len(data), l2TxFixedSize)
+	}
+
+	encodedLength := binary.BigEndian.Uint32(data[34:38])
+	if uint32(len(data)-l2TxFixedSize) != encodedLength {
+		return nil, fmt.Errorf("invalid L2 tx entry length %d, expected %d", len(data), l2TxFixedSize+int(encodedLength))
+	}
+
+	return &L2Transaction{
+		EffectiveGasPricePercentage: data[0],
+		IsValid:                     data[1],
+		StateRoot:                   common.BytesToHash(data[2:34]),
+		Encoded:                     data[38:],
+	}, nil
+}
+
+// Encode encodes the entry to the binary format of the data stream
+func (b *L2BlockEnd) Encode() []byte {
+	data := make([]byte, 0, l2BlockEndSize)
+	data = binary.BigEndian.AppendUint64(data, b.L2BlockNumber)
+	data = append(data, b.BlockHash.Bytes()...)
+	data = append(data, b.StateRoot.Bytes()...)
+	return data
+}
+
+// DecodeL2BlockEnd decodes the entry that closes a L2 block from the binary format of the data stream
+func DecodeL2BlockEnd(data []byte) (*L2BlockEnd, error) {
+	if len(data) != l2BlockEndSize {
+		return nil, fmt.Errorf("invalid L2 block end entry length %d, expected %d", len(data), l2BlockEndSize)
+	}
+
+	return &L2BlockEnd{
+		L2BlockNumber: binary.BigEndian.Uint64(data[0:8]),
+		BlockHash:     common.BytesToHash(data[8:40]),
+		StateRoot:     common.BytesToHash(data[40:72]),
+	}, nil
+}
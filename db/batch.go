@@ -0,0 +1,96 @@
+package db
+
+import (
+	"context"
+	"time"
+
+	"github.com/0xPolygonHermez/zkevm-pool-manager/types"
+)
+
+// GetL2BlocksWithoutBatchNumber returns the numbers of the L2 blocks of the receipts of the confirmed and failed txs
+// whose batch number is not known yet, lowest first, up to limit blocks
+func (p *PoolDB) GetL2BlocksWithoutBatchNumber(ctx context.Context, limit uint64) ([]uint64, error) {
+	const getBlocksSQL = `
+		SELECT DISTINCT r.block_number FROM pool.receipt r JOIN pool.transaction t ON t.id = r.tx_id
+		WHERE r.batch_number IS NULL AND t.status IN ($1, $2)
+		ORDER BY r.block_number LIMIT $3
+	`
+
+	rows, err := p.db.Query(ctx, getBlocksSQL, types.TxStatusConfirmed, types.TxStatusFailed, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	blocks := []uint64{}
+	for rows.Next() {
+		var block uint64
+		if err := rows.Scan(&block); err != nil {
+			return nil, err
+		}
+		blocks = append(blocks, block)
+	}
+
+	return blocks, rows.Err()
+}
+
+// UpdateL2BlockBatchNumber sets the batch number of the receipts of the txs included in the L2 block
+func (p *PoolDB) UpdateL2BlockBatchNumber(ctx context.Context, blockNumber uint64, batchNumber uint64) error {
+	const updateBatchSQL = "UPDATE pool.receipt SET batch_number = $2 WHERE block_number = $1"
+
+	_, err := p.db.Exec(ctx, updateBatchSQL, blockNumber, batchNumber)
+	return err
+}
+
+// UpdateL2TransactionsBatchStatus updates to virtualized the status of the confirmed txs included in a batch lower or
+// equal than the virtualBatch, and to verified the status of the confirmed and virtualized txs included in a batch lower
+// or equal than the verifiedBatch. The failed txs keep their status, as it's final, and only their virtualization and
+// verification times are set. It returns the number of virtualized and verified txs
+func (p *PoolDB) UpdateL2TransactionsBatchStatus(ctx context.Context, virtualBatch uint64, verifiedBatch uint64) (int64, int64, error) {
+	const updateVirtualizedSQL = `
+		UPDATE pool.transaction t SET updated_at = $1, status = $2, virtualized_at = $1
+		FROM pool.receipt r
+		WHERE r.tx_id = t.id AND t.status = $3 AND r.batch_number <= $4
+	`
+	const updateVerifiedSQL = `
+		UPDATE pool.transaction t SET updated_at = $1, status = $2, virtualized_at = COALESCE(t.virtualized_at, $1), verified_at = $1
+		FROM pool.receipt r
+		WHERE r.tx_id = t.id AND t.status IN ($3, $4) AND r.batch_number <= $5
+	`
+	const updateFailedVirtualizedSQL = `
+		UPDATE pool.transaction t SET virtualized_at = $1
+		FROM pool.receipt r
+		WHERE r.tx_id = t.id AND t.status = $2 AND t.virtualized_at IS NULL AND r.batch_number <= $3
+	`
+	const updateFailedVerifiedSQL = `
+		UPDATE pool.transaction t SET virtualized_at = COALESCE(t.virtualized_at, $1), verified_at = $1
+		FROM pool.receipt r
+		WHERE r.tx_id = t.id AND t.status = $2 AND t.verified_at IS NULL AND r.batch_number <= $3
+	`
+
+	dbTx, err := p.db.Begin(ctx)
+	if err != nil {
+		return 0, 0, err
+	}
+	defer func() { _ = dbTx.Rollback(ctx) }()
+
+	now := time.Now()
+	virtualized, err := dbTx.Exec(ctx, updateVirtualizedSQL, now, types.TxStatusVirtualized, types.TxStatusConfirmed, virtualBatch)
+	if err != nil {
+		return 0, 0, err
+	}
+	verified, err := dbTx.Exec(ctx, updateVerifiedSQL, now, types.TxStatusVerified, types.TxStatusConfirmed, types.TxStatusVirtualized, verifiedBatch)
+	if err != nil {
+		return 0, 0, err
+	}
+	failedVirtualized, err := dbTx.Exec(ctx, updateFailedVirtualizedSQL, now, types.TxStatusFailed, virtualBatch)
+	if err != nil {
+		return 0, 0, err
+	}
+	failedVerified, err := dbTx.Exec(ctx, updateFailedVerifiedSQL, now, types.TxStatusFailed, verifiedBatch)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	return virtualized.RowsAffected() + failedVirtualized.RowsAffected(), verified.RowsAffected() + failedVerified.RowsAffected(), dbTx.Commit(ctx)
+}
@@ -1,5 +1,7 @@
 package db
 
+import "github.com/0xPolygonHermez/zkevm-pool-manager/config/types"
+
 // Config provide fields to configure the pool
 type Config struct {
 	// Database name
@@ -22,4 +24,23 @@ type Config struct {
 
 	// MaxConns is the maximum number of connections in the pool.
 	MaxConns int `mapstructure:"MaxConns"`
+
+	// Lease is the configuration of the ownership of the txs when several pool-manager instances share the database
+	Lease LeaseConfig `mapstructure:"Lease"`
+}
+
+// LeaseConfig provide fields to configure the lease-based ownership of the txs
+type LeaseConfig struct {
+	// Enabled defines if the txs are claimed with a lease before being processed
+	Enabled bool `mapstructure:"Enabled"`
+
+	// OwnerID is the id of the pool-manager instance that owns the leases. If empty a random id is generated at startup.
+	// It must be set, and stable across restarts, if the monitor reads the L2 blocks from the data stream
+	OwnerID string `mapstructure:"OwnerID"`
+
+	// Duration is the time a lease is valid. After this time without renewal the txs can be claimed by other instances
+	Duration types.Duration `mapstructure:"Duration"`
+
+	// HeartbeatInterval is the time between renewals of the leases owned by the instance
+	HeartbeatInterval types.Duration `mapstructure:"HeartbeatInterval"`
 }
@@ -0,0 +1,27 @@
+package db
+
+import (
+	"context"
+	"time"
+)
+
+// GetDataStreamCheckpoint returns the number of the last L2 block read from the data stream server. Returns
+// pgx.ErrNoRows if no block has been read from the server yet
+func (p *PoolDB) GetDataStreamCheckpoint(ctx context.Context, server string) (uint64, error) {
+	const getCheckpointSQL = "SELECT block_number FROM pool.datastream_checkpoint WHERE server = $1"
+
+	var blockNumber uint64
+	err := p.db.QueryRow(ctx, getCheckpointSQL, server).Scan(&blockNumber)
+	return blockNumber, err
+}
+
+// UpdateDataStreamCheckpoint sets the number of the last L2 block read from the data stream server
+func (p *PoolDB) UpdateDataStreamCheckpoint(ctx context.Context, server string, blockNumber uint64) error {
+	const updateCheckpointSQL = `
+		INSERT INTO pool.datastream_checkpoint (server, block_number, updated_at) VALUES ($1, $2, $3)
+		ON CONFLICT (server) DO UPDATE SET block_number = EXCLUDED.block_number, updated_at = EXCLUDED.updated_at
+	`
+
+	_, err := p.db.Exec(ctx, updateCheckpointSQL, server, blockNumber, time.Now())
+	return err
+}
@@ -0,0 +1,207 @@
+package db
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/0xPolygonHermez/zkevm-pool-manager/types"
+	"github.com/jackc/pgx/v4"
+)
+
+// deadLetterColumns are the columns of the pool.dead_letter (d) and pool.transaction (t) tables read by scanDeadLetter
+const deadLetterColumns = `d.tx_id, t.hash, t.from_address, t.nonce, d.status, d.category, COALESCE(d.error, ''), t.received_at,
+	d.created_at, t.attempt_count, t.first_sent_at, t.last_sent_at, d.requeue_count, t.encoded`
+
+// requeueErrorMsg is the message added to the error history of the txs requeued from the dead-letter store
+const requeueErrorMsg = "requeued from the dead-letter store"
+
+// GetDeadLetters returns the dead-lettered txs that match the filter, the most recent first
+func (p *PoolDB) GetDeadLetters(ctx context.Context, filter types.DeadLetterFilter) ([]*types.DeadLetter, error) {
+	condition, args := deadLetterCondition(filter)
+	sql := fmt.Sprintf(`
+		SELECT %s FROM pool.dead_letter d JOIN pool.transaction t ON t.id = d.tx_id
+		WHERE %s ORDER BY d.created_at DESC %s
+	`, deadLetterColumns, condition, deadLetterLimit(filter))
+
+	rows, err := p.db.Query(ctx, sql, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	deadLetters := []*types.DeadLetter{}
+	for rows.Next() {
+		deadLetter, err := scanDeadLetter(rows)
+		if err != nil {
+			return nil, err
+		}
+		deadLetters = append(deadLetters, deadLetter)
+	}
+
+	return deadLetters, rows.Err()
+}
+
+// GetDeadLetter returns the dead-lettered tx with its error history. Returns pgx.ErrNoRows if the tx is not dead-lettered
+func (p *PoolDB) GetDeadLetter(ctx context.Context, txID uint64) (*types.DeadLetter, error) {
+	sql := fmt.Sprintf(`
+		SELECT %s FROM pool.dead_letter d JOIN pool.transaction t ON t.id = d.tx_id
+		WHERE d.tx_id = $1 AND d.requeued_at IS NULL
+	`, deadLetterColumns)
+
+	deadLetter, err := scanDeadLetter(p.db.QueryRow(ctx, sql, txID))
+	if err != nil {
+		return nil, err
+	}
+
+	const errorsSQL = "SELECT created_at, source, COALESCE(status, ''), error FROM pool.transaction_error WHERE tx_id = $1 ORDER BY id"
+
+	rows, err := p.db.Query(ctx, errorsSQL, txID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	deadLetter.Errors = []*types.L2TransactionError{}
+	for rows.Next() {
+		txError := &types.L2TransactionError{}
+		err := rows.Scan(&txError.CreatedAt, &txError.Source, &txError.Status, &txError.Error)
+		if err != nil {
+			return nil, err
+		}
+		deadLetter.Errors = append(deadLetter.Errors, txError)
+	}
+
+	return deadLetter, rows.Err()
+}
+
+// RequeueDeadLetters moves the dead-lettered txs that match the filter back to resend status, resetting their send
+// attempts so they are sent again. The lifetime of the requeued txs is counted again from the requeue time, kept apart
+// from the received time, so they are not expired right away. The reverted txs are not requeued, as
+// they have been mined and their nonce is used. It returns the number of requeued txs
+func (p *PoolDB) RequeueDeadLetters(ctx context.Context, filter types.DeadLetterFilter) (int64, error) {
+	condition, args := deadLetterCondition(filter)
+	n := len(args)
+	sql := fmt.Sprintf(`
+		WITH requeued AS (
+			UPDATE pool.dead_letter SET requeue_count = requeue_count + 1, requeued_at = $%[1]d
+			WHERE tx_id IN (SELECT d.tx_id FROM pool.dead_letter d WHERE %[4]s AND d.status <> $%[7]d ORDER BY d.created_at DESC %[5]s)
+			RETURNING tx_id
+		), updated AS (
+			UPDATE pool.transaction t
+			SET status = $%[2]d, updated_at = $%[1]d, requeued_at = $%[1]d, error = NULL, attempt_count = 0, first_sent_at = NULL,
+				last_sent_at = NULL, last_error = NULL, owner_id = NULL, lease_expires_at = NULL, expiry_resends = 0, next_check_at = NULL
+			FROM requeued r WHERE t.id = r.tx_id
+			RETURNING t.id
+		)
+		INSERT INTO pool.transaction_error (tx_id, created_at, source, status, error)
+		SELECT id, $%[1]d, $%[3]d, $%[2]d, '%[6]s' FROM updated
+	`, n+1, n+2, n+3, condition, deadLetterLimit(filter), requeueErrorMsg, n+4)
+
+	args = append(args, time.Now(), types.TxStatusResend, types.TxErrorSourceAdmin, types.TxStatusFailed)
+	result, err := p.db.Exec(ctx, sql, args...)
+	if err != nil {
+		return 0, err
+	}
+
+	return result.RowsAffected(), nil
+}
+
+// PurgeDeadLetters deletes from the pool database the dead-lettered txs that match the filter, with their error
+// history. It returns the number of purged txs
+func (p *PoolDB) PurgeDeadLetters(ctx context.Context, filter types.DeadLetterFilter) (int64, error) {
+	condition, args := deadLetterCondition(filter)
+	sql := fmt.Sprintf(`
+		DELETE FROM pool.transaction
+		WHERE id IN (SELECT d.tx_id FROM pool.dead_letter d WHERE %s ORDER BY d.created_at DESC %s)
+	`, condition, deadLetterLimit(filter))
+
+	result, err := p.db.Exec(ctx, sql, args...)
+	if err != nil {
+		return 0, err
+	}
+
+	return result.RowsAffected(), nil
+}
+
+// addL2TransactionError adds an error to the error history of the tx
+func addL2TransactionError(ctx context.Context, dbTx pgx.Tx, txID uint64, createdAt time.Time, source string, status string, errorMsg string) error {
+	const addErrorSQL = "INSERT INTO pool.transaction_error (tx_id, created_at, source, status, error) VALUES ($1, $2, $3, NULLIF($4, ''), $5)"
+
+	_, err := dbTx.Exec(ctx, addErrorSQL, txID, createdAt, source, status, errorMsg)
+	return err
+}
+
+// addDeadLetter moves the tx to the dead-letter store. If the tx was dead-lettered before and requeued, its entry is
+// updated keeping the requeue count
+func addDeadLetter(ctx context.Context, dbTx pgx.Tx, txID uint64, createdAt time.Time, status string, errorMsg string) error {
+	const addDeadLetterSQL = `
+		INSERT INTO pool.dead_letter (tx_id, created_at, status, category, error) VALUES ($1, $2, $3, $4, NULLIF($5, ''))
+		ON CONFLICT (tx_id) DO UPDATE
+		SET created_at = EXCLUDED.created_at, status = EXCLUDED.status, category = EXCLUDED.category, error = EXCLUDED.error, requeued_at = NULL
+	`
+
+	_, err := dbTx.Exec(ctx, addDeadLetterSQL, txID, createdAt, status, types.DeadLetterCategory(status, errorMsg), errorMsg)
+	return err
+}
+
+// deadLetterCondition returns the SQL condition on the pool.dead_letter (d) table for the filter and its args. Only the
+// txs that have not been requeued are selected
+func deadLetterCondition(filter types.DeadLetterFilter) (string, []interface{}) {
+	conditions := []string{"d.requeued_at IS NULL"}
+	args := []interface{}{}
+
+	if len(filter.TxIds) > 0 {
+		ids := make([]int64, len(filter.TxIds))
+		for i, id := range filter.TxIds {
+			ids[i] = int64(id)
+		}
+		args = append(args, ids)
+		conditions = append(conditions, fmt.Sprintf("d.tx_id = ANY($%d)", len(args)))
+	}
+	if len(filter.Categories) > 0 {
+		args = append(args, filter.Categories)
+		conditions = append(conditions, fmt.Sprintf("d.category = ANY($%d)", len(args)))
+	}
+	if !filter.From.IsZero() {
+		args = append(args, filter.From)
+		conditions = append(conditions, fmt.Sprintf("d.created_at >= $%d", len(args)))
+	}
+	if !filter.To.IsZero() {
+		args = append(args, filter.To)
+		conditions = append(conditions, fmt.Sprintf("d.created_at < $%d", len(args)))
+	}
+
+	return strings.Join(conditions, " AND "), args
+}
+
+// deadLetterLimit returns the SQL limit clause for the filter
+func deadLetterLimit(filter types.DeadLetterFilter) string {
+	if filter.Limit == 0 {
+		return ""
+	}
+	return fmt.Sprintf("LIMIT %d", filter.Limit)
+}
+
+// scanDeadLetter reads a dead-lettered tx from a row with the deadLetterColumns
+func scanDeadLetter(row pgx.Row) (*types.DeadLetter, error) {
+	deadLetter := &types.DeadLetter{}
+	var firstSentAt, lastSentAt *time.Time
+
+	err := row.Scan(&deadLetter.TxId, &deadLetter.Hash, &deadLetter.FromAddress, &deadLetter.Nonce, &deadLetter.Status, &deadLetter.Category,
+		&deadLetter.Error, &deadLetter.ReceivedAt, &deadLetter.CreatedAt, &deadLetter.AttemptCount, &firstSentAt, &lastSentAt,
+		&deadLetter.RequeueCount, &deadLetter.Encoded)
+	if err != nil {
+		return nil, err
+	}
+
+	if firstSentAt != nil {
+		deadLetter.FirstSentAt = *firstSentAt
+	}
+	if lastSentAt != nil {
+		deadLetter.LastSentAt = *lastSentAt
+	}
+
+	return deadLetter, nil
+}
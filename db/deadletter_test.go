@@ -0,0 +1,89 @@
+package db
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	poolTypes "github.com/0xPolygonHermez/zkevm-pool-manager/types"
+	"github.com/jackc/pgx/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDeadLetters(t *testing.T) {
+	ctx := context.Background()
+	poolDB := newTestPoolDB(t, LeaseConfig{})
+	resetTestPoolDB(t, poolDB)
+
+	invalid := addTestL2Transaction(t, poolDB, poolTypes.TxStatusPending)
+	require.NoError(t, poolDB.UpdateL2TransactionStatus(ctx, invalid.Id, poolTypes.TxStatusInvalid, "nonce too high"))
+	reverted := addTestL2Transaction(t, poolDB, poolTypes.TxStatusSent)
+	require.NoError(t, poolDB.UpdateL2TransactionStatus(ctx, reverted.Id, poolTypes.TxStatusFailed, ""))
+	expired := &poolTypes.L2Transaction{Hash: "0x03", ReceivedAt: time.Now().Add(-2 * time.Hour), FromAddress: "0xabc", Status: poolTypes.TxStatusSent, Decoded: "{}"}
+	var err error
+	expired.Id, err = poolDB.AddL2Transaction(ctx, expired)
+	require.NoError(t, err)
+	expiredCount, _, err := poolDB.UpdateExpiredL2Transactions(ctx, time.Hour, false, 0, nil)
+	require.NoError(t, err)
+	require.Equal(t, int64(1), expiredCount)
+
+	deadLetters, err := poolDB.GetDeadLetters(ctx, poolTypes.DeadLetterFilter{})
+	require.NoError(t, err)
+	assert.ElementsMatch(t, []uint64{invalid.Id, reverted.Id, expired.Id}, deadLetterIds(deadLetters))
+	deadLetters, err = poolDB.GetDeadLetters(ctx, poolTypes.DeadLetterFilter{Categories: []string{poolTypes.DeadLetterCategoryNonce}})
+	require.NoError(t, err)
+	assert.Equal(t, []uint64{invalid.Id}, deadLetterIds(deadLetters))
+
+	// The reverted tx is not requeued, as it has been mined
+	requeued, err := poolDB.RequeueDeadLetters(ctx, poolTypes.DeadLetterFilter{TxIds: []uint64{invalid.Id, reverted.Id, expired.Id}})
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), requeued)
+
+	_, err = poolDB.GetDeadLetter(ctx, expired.Id)
+	assert.ErrorIs(t, err, pgx.ErrNoRows)
+	deadLetter, err := poolDB.GetDeadLetter(ctx, reverted.Id)
+	require.NoError(t, err)
+	assert.Equal(t, poolTypes.DeadLetterCategoryReverted, deadLetter.Category)
+
+	resend, err := poolDB.GetL2TransactionsToResend(ctx)
+	require.NoError(t, err)
+	require.ElementsMatch(t, []uint64{invalid.Id, expired.Id}, ids(resend))
+	// The received time is kept, the lifetime is counted from the requeue time
+	for _, l2Tx := range resend {
+		assert.Zero(t, l2Tx.AttemptCount)
+		assert.WithinDuration(t, time.Now(), l2Tx.RequeuedAt, time.Minute)
+		if l2Tx.Id == expired.Id {
+			assert.WithinDuration(t, expired.ReceivedAt, l2Tx.ReceivedAt, time.Second)
+		}
+	}
+
+	// The lifetime of the requeued tx is counted from the requeue, so it's not expired again when it's sent
+	require.NoError(t, poolDB.UpdateL2TransactionStatus(ctx, expired.Id, poolTypes.TxStatusSent, ""))
+	expiredCount, _, err = poolDB.UpdateExpiredL2Transactions(ctx, time.Hour, false, 0, nil)
+	require.NoError(t, err)
+	assert.Zero(t, expiredCount)
+
+	// Requeued txs that fail again are dead-lettered again, keeping the requeue count
+	require.NoError(t, poolDB.UpdateL2TransactionStatus(ctx, invalid.Id, poolTypes.TxStatusInvalid, "nonce too high"))
+	deadLetter, err = poolDB.GetDeadLetter(ctx, invalid.Id)
+	require.NoError(t, err)
+	assert.Equal(t, uint64(1), deadLetter.RequeueCount)
+	assert.Len(t, deadLetter.Errors, 3)
+
+	purged, err := poolDB.PurgeDeadLetters(ctx, poolTypes.DeadLetterFilter{Categories: []string{poolTypes.DeadLetterCategoryReverted}})
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), purged)
+	deadLetters, err = poolDB.GetDeadLetters(ctx, poolTypes.DeadLetterFilter{})
+	require.NoError(t, err)
+	assert.Equal(t, []uint64{invalid.Id}, deadLetterIds(deadLetters))
+}
+
+// deadLetterIds returns the tx ids of the dead-lettered txs
+func deadLetterIds(deadLetters []*poolTypes.DeadLetter) []uint64 {
+	ids := []uint64{}
+	for _, deadLetter := range deadLetters {
+		ids = append(ids, deadLetter.TxId)
+	}
+	return ids
+}
@@ -0,0 +1,34 @@
+package db
+
+import (
+	"context"
+	"time"
+
+	"github.com/0xPolygonHermez/zkevm-pool-manager/types"
+)
+
+// expiredTxError is the error recorded in the error history of the expired txs that are resent
+const expiredTxError = "tx expired waiting for the receipt"
+
+// ResendExpiredL2Transaction moves the sent tx that has reached its lifetime to resend status, so the sender sends it
+// again, and increases the number of times it has been resent because it expired. The status is only updated if the
+// tx is still sent. Returns false if the tx has not been updated
+func (p *PoolDB) ResendExpiredL2Transaction(ctx context.Context, id uint64) (bool, error) {
+	const resendExpiredSQL = `
+		WITH resent AS (
+			UPDATE pool.transaction SET updated_at = $2, status = $3, error = $5, expiry_resends = expiry_resends + 1
+			WHERE id = $1 AND status = $4
+			RETURNING id
+		)
+		INSERT INTO pool.transaction_error (tx_id, created_at, source, status, error)
+		SELECT id, $2, $6, $3, $5 FROM resent
+	`
+
+	result, err := p.db.Exec(ctx, resendExpiredSQL, id, time.Now(), types.TxStatusResend, types.TxStatusSent, expiredTxError,
+		types.TxErrorSourceStatus)
+	if err != nil {
+		return false, err
+	}
+
+	return result.RowsAffected() > 0, nil
+}
@@ -0,0 +1,13 @@
+-- +migrate Down
+ALTER TABLE pool.transaction
+    DROP COLUMN IF EXISTS attempt_count,
+    DROP COLUMN IF EXISTS first_sent_at,
+    DROP COLUMN IF EXISTS last_sent_at,
+    DROP COLUMN IF EXISTS last_error;
+
+-- +migrate Up
+ALTER TABLE pool.transaction
+    ADD COLUMN attempt_count   INTEGER NOT NULL DEFAULT 0,
+    ADD COLUMN first_sent_at   TIMESTAMP WITH TIME ZONE,
+    ADD COLUMN last_sent_at    TIMESTAMP WITH TIME ZONE,
+    ADD COLUMN last_error      VARCHAR;
@@ -0,0 +1,12 @@
+-- +migrate Down
+DROP INDEX IF EXISTS pool.transaction_status_idx;
+ALTER TABLE pool.transaction
+    DROP COLUMN IF EXISTS owner_id,
+    DROP COLUMN IF EXISTS lease_expires_at;
+
+-- +migrate Up
+ALTER TABLE pool.transaction
+    ADD COLUMN owner_id          VARCHAR,
+    ADD COLUMN lease_expires_at  TIMESTAMP WITH TIME ZONE;
+
+CREATE INDEX transaction_status_idx ON pool.transaction (status);
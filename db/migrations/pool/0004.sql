@@ -0,0 +1,18 @@
+-- +migrate Down
+DROP TRIGGER IF EXISTS transaction_notify ON pool.transaction;
+DROP FUNCTION IF EXISTS pool.notify_transaction();
+
+-- +migrate Up
+-- +migrate StatementBegin
+CREATE OR REPLACE FUNCTION pool.notify_transaction() RETURNS TRIGGER AS $$
+BEGIN
+    IF NEW.status IN ('pending', 'resend') AND (TG_OP = 'INSERT' OR OLD.status IS DISTINCT FROM NEW.status) THEN
+        PERFORM pg_notify('pool_transaction', json_build_object('id', NEW.id, 'status', NEW.status, 'ownerId', COALESCE(NEW.owner_id, ''))::text);
+    END IF;
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+-- +migrate StatementEnd
+
+CREATE TRIGGER transaction_notify AFTER INSERT OR UPDATE OF status ON pool.transaction
+    FOR EACH ROW EXECUTE FUNCTION pool.notify_transaction();
@@ -0,0 +1,18 @@
+-- +migrate Down
+DROP TABLE IF EXISTS pool.shadow_send;
+
+-- +migrate Up
+CREATE TABLE pool.shadow_send
+(
+    id                  SERIAL PRIMARY KEY,
+    tx_id               BIGINT NOT NULL,
+    hash                VARCHAR NOT NULL,
+    sent_at             TIMESTAMP WITH TIME ZONE NOT NULL,
+    primary_error       VARCHAR,
+    primary_latency_ms  BIGINT NOT NULL,
+    shadow_url          VARCHAR NOT NULL,
+    shadow_error        VARCHAR,
+    shadow_latency_ms   BIGINT NOT NULL
+);
+
+CREATE INDEX shadow_send_tx_id_idx ON pool.shadow_send (tx_id);
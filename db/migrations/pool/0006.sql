@@ -0,0 +1,29 @@
+-- +migrate Down
+DROP TABLE IF EXISTS pool.dead_letter;
+DROP TABLE IF EXISTS pool.transaction_error;
+
+-- +migrate Up
+CREATE TABLE pool.transaction_error
+(
+    id              SERIAL PRIMARY KEY,
+    tx_id           BIGINT NOT NULL REFERENCES pool.transaction (id) ON DELETE CASCADE,
+    created_at      TIMESTAMP WITH TIME ZONE NOT NULL,
+    source          VARCHAR NOT NULL,
+    status          VARCHAR,
+    error           VARCHAR NOT NULL
+);
+
+CREATE INDEX transaction_error_tx_id_idx ON pool.transaction_error (tx_id);
+
+CREATE TABLE pool.dead_letter
+(
+    tx_id           BIGINT PRIMARY KEY REFERENCES pool.transaction (id) ON DELETE CASCADE,
+    created_at      TIMESTAMP WITH TIME ZONE NOT NULL,
+    status          VARCHAR NOT NULL,
+    category        VARCHAR NOT NULL,
+    error           VARCHAR,
+    requeue_count   INTEGER NOT NULL DEFAULT 0,
+    requeued_at     TIMESTAMP WITH TIME ZONE
+);
+
+CREATE INDEX dead_letter_category_idx ON pool.dead_letter (category, created_at);
@@ -0,0 +1,19 @@
+-- +migrate Down
+DROP TABLE IF EXISTS pool.receipt;
+
+-- +migrate Up
+CREATE TABLE pool.receipt
+(
+    tx_id               BIGINT PRIMARY KEY REFERENCES pool.transaction (id) ON DELETE CASCADE,
+    created_at          TIMESTAMP WITH TIME ZONE NOT NULL,
+    status              BIGINT NOT NULL,
+    block_number        BIGINT NOT NULL,
+    block_hash          VARCHAR NOT NULL,
+    tx_index            BIGINT NOT NULL,
+    gas_used            BIGINT NOT NULL,
+    effective_gas_price DECIMAL(78, 0),
+    contract_address    VARCHAR,
+    logs_bloom          BYTEA
+);
+
+CREATE INDEX receipt_block_number_idx ON pool.receipt (block_number);
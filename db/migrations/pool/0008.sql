@@ -0,0 +1,17 @@
+-- +migrate Down
+DROP INDEX IF EXISTS pool.receipt_batch_number_idx;
+ALTER TABLE pool.receipt
+    DROP COLUMN IF EXISTS batch_number;
+ALTER TABLE pool.transaction
+    DROP COLUMN IF EXISTS virtualized_at,
+    DROP COLUMN IF EXISTS verified_at;
+
+-- +migrate Up
+ALTER TABLE pool.transaction
+    ADD COLUMN virtualized_at  TIMESTAMP WITH TIME ZONE,
+    ADD COLUMN verified_at     TIMESTAMP WITH TIME ZONE;
+
+ALTER TABLE pool.receipt
+    ADD COLUMN batch_number    BIGINT;
+
+CREATE INDEX receipt_batch_number_idx ON pool.receipt (batch_number);
@@ -0,0 +1,17 @@
+-- +migrate Down
+DROP TABLE IF EXISTS pool.reorg;
+
+-- +migrate Up
+CREATE TABLE pool.reorg
+(
+    id              SERIAL PRIMARY KEY,
+    tx_id           BIGINT NOT NULL REFERENCES pool.transaction (id) ON DELETE CASCADE,
+    detected_at     TIMESTAMP WITH TIME ZONE NOT NULL,
+    block_number    BIGINT NOT NULL,
+    block_hash      VARCHAR NOT NULL,
+    canonical_hash  VARCHAR,
+    previous_status VARCHAR NOT NULL,
+    new_status      VARCHAR NOT NULL
+);
+
+CREATE INDEX reorg_tx_id_idx ON pool.reorg (tx_id);
@@ -0,0 +1,10 @@
+-- +migrate Down
+DROP TABLE IF EXISTS pool.datastream_checkpoint;
+
+-- +migrate Up
+CREATE TABLE pool.datastream_checkpoint
+(
+    server       VARCHAR PRIMARY KEY,
+    block_number BIGINT NOT NULL,
+    updated_at   TIMESTAMP WITH TIME ZONE NOT NULL
+);
@@ -0,0 +1,10 @@
+-- +migrate Down
+DROP INDEX IF EXISTS pool.transaction_from_address_nonce_idx;
+ALTER TABLE pool.transaction
+    DROP COLUMN IF EXISTS replaced_by;
+
+-- +migrate Up
+ALTER TABLE pool.transaction
+    ADD COLUMN replaced_by     VARCHAR;
+
+CREATE INDEX transaction_from_address_nonce_idx ON pool.transaction (from_address, nonce);
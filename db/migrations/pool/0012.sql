@@ -0,0 +1,9 @@
+-- +migrate Down
+ALTER TABLE pool.transaction
+    DROP COLUMN IF EXISTS lifetime,
+    DROP COLUMN IF EXISTS expiry_resends;
+
+-- +migrate Up
+ALTER TABLE pool.transaction
+    ADD COLUMN lifetime        BIGINT,
+    ADD COLUMN expiry_resends  INTEGER NOT NULL DEFAULT 0;
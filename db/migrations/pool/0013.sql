@@ -0,0 +1,9 @@
+-- +migrate Down
+ALTER TABLE pool.transaction
+    DROP COLUMN IF EXISTS next_check_at,
+    DROP COLUMN IF EXISTS check_count;
+
+-- +migrate Up
+ALTER TABLE pool.transaction
+    ADD COLUMN next_check_at   TIMESTAMP WITH TIME ZONE,
+    ADD COLUMN check_count     INTEGER NOT NULL DEFAULT 0;
@@ -0,0 +1,7 @@
+-- +migrate Down
+ALTER TABLE pool.transaction
+    DROP COLUMN IF EXISTS api_key;
+
+-- +migrate Up
+ALTER TABLE pool.transaction
+    ADD COLUMN api_key VARCHAR;
@@ -0,0 +1,7 @@
+-- +migrate Down
+ALTER TABLE pool.transaction
+    DROP COLUMN IF EXISTS requeued_at;
+
+-- +migrate Up
+ALTER TABLE pool.transaction
+    ADD COLUMN requeued_at TIMESTAMP WITH TIME ZONE;
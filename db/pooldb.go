@@ -2,15 +2,29 @@ package db
 
 import (
 	"context"
+	"encoding/json"
+	"fmt"
 	"time"
 
+	"github.com/0xPolygonHermez/zkevm-pool-manager/log"
 	"github.com/0xPolygonHermez/zkevm-pool-manager/types"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v4"
 	"github.com/jackc/pgx/v4/pgxpool"
 )
 
+// l2TransactionChannel is the channel where the pool database notifies the txs that change to pending or resend status
+const l2TransactionChannel = "pool_transaction"
+
+// l2TransactionColumns are the columns of the pool.transaction table read by scanL2Transaction
+const l2TransactionColumns = "id, hash, received_at, from_address, gas_price, nonce, status, ip, encoded, decoded, attempt_count, first_sent_at, last_sent_at, COALESCE(last_error, ''), " +
+	"COALESCE(lifetime, 0), expiry_resends, next_check_at, check_count, COALESCE(api_key, ''), requeued_at"
+
 // PoolDB represent a postgres pool database to store transactions
 type PoolDB struct {
-	db *pgxpool.Pool
+	cfg     Config
+	db      *pgxpool.Pool
+	ownerID string
 }
 
 // NewPostgresPoolStorage creates and initializes an instance of PostgresPoolStorage
@@ -20,21 +34,45 @@ func NewPoolDB(cfg Config) (*PoolDB, error) {
 		return nil, err
 	}
 
-	return &PoolDB{db: poolDB}, nil
+	ownerID := cfg.Lease.OwnerID
+	if cfg.Lease.Enabled && ownerID == "" {
+		ownerID = uuid.NewString()
+	}
+
+	return &PoolDB{cfg: cfg, db: poolDB, ownerID: ownerID}, nil
+}
+
+// Close closes the connections to the pool database
+func (p *PoolDB) Close() {
+	p.db.Close()
+}
+
+// OwnerID returns the id used by the instance to own the leases of the txs
+func (p *PoolDB) OwnerID() string {
+	return p.ownerID
 }
 
 // AddTx adds a L2 transaction to the pool
 func (p *PoolDB) AddL2Transaction(ctx context.Context, tx *types.L2Transaction) (uint64, error) {
 	const sql = `
 		INSERT INTO pool.transaction 
-		(hash, received_at,	updated_at, from_address, gas_price, nonce,	status,	ip, encoded, decoded) 
-		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
+		(hash, received_at,	updated_at, from_address, gas_price, nonce,	status,	ip, encoded, decoded, owner_id, lease_expires_at, lifetime, api_key) 
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, NULLIF($13, 0), NULLIF($14, ''))
 		RETURNING id
 	`
 
 	var id uint64
 
-	err := p.db.QueryRow(ctx, sql, tx.Hash, tx.ReceivedAt, time.Now(), tx.FromAddress, tx.GasPrice, tx.Nonce, tx.Status, tx.IP, tx.Encoded, tx.Decoded).Scan(&id)
+	// If leases are enabled the tx is owned by this instance since it's received
+	var ownerID *string
+	var leaseExpiresAt *time.Time
+	if p.cfg.Lease.Enabled {
+		expiresAt := time.Now().Add(p.cfg.Lease.Duration.Duration)
+		ownerID, leaseExpiresAt = &p.ownerID, &expiresAt
+	}
+
+	err := p.db.QueryRow(ctx, sql, tx.Hash, tx.ReceivedAt, time.Now(), tx.FromAddress, tx.GasPrice, tx.Nonce, tx.Status, tx.IP, tx.Encoded, tx.Decoded, ownerID, leaseExpiresAt,
+		int64(tx.Lifetime.Seconds()), tx.APIKey).Scan(&id)
 	if err != nil {
 		return 0, err
 	}
@@ -42,21 +80,142 @@ func (p *PoolDB) AddL2Transaction(ctx context.Context, tx *types.L2Transaction)
 	return id, nil
 }
 
+// GetL2TransactionsByStatus returns the txs with the given status. If leases are enabled only the txs that are not
+// owned by other instances are returned, and they are claimed for this instance
 func (p *PoolDB) GetL2TransactionsByStatus(ctx context.Context, status string) ([]*types.L2Transaction, error) {
-	const resendTxsSQL = "SELECT id, hash, received_at, from_address, gas_price, nonce, status, ip, encoded, decoded FROM pool.transaction WHERE status = $1"
+	if p.cfg.Lease.Enabled {
+		return p.claimL2Transactions(ctx, "status = $1", status)
+	}
+
+	const resendTxsSQL = "SELECT " + l2TransactionColumns + " FROM pool.transaction WHERE status = $1"
+
+	return p.queryL2Transactions(ctx, resendTxsSQL, status)
+}
+
+func (p *PoolDB) GetL2TransactionsToResend(ctx context.Context) ([]*types.L2Transaction, error) {
+	return p.GetL2TransactionsByStatus(ctx, types.TxStatusResend)
+}
+
+func (p *PoolDB) GetL2TransactionsToMonitor(ctx context.Context) ([]*types.L2Transaction, error) {
+	return p.GetL2TransactionsByStatus(ctx, types.TxStatusSent)
+}
+
+func (p *PoolDB) GetL2TransactionsToSend(ctx context.Context) ([]*types.L2Transaction, error) {
+	return p.GetL2TransactionsByStatus(ctx, types.TxStatusPending)
+}
+
+// GetL2TransactionsToRecover returns the pending txs received before the receivedBefore time
+func (p *PoolDB) GetL2TransactionsToRecover(ctx context.Context, receivedBefore time.Time) ([]*types.L2Transaction, error) {
+	if p.cfg.Lease.Enabled {
+		return p.claimL2Transactions(ctx, "status = $1 AND received_at < $2", types.TxStatusPending, receivedBefore)
+	}
+
+	const recoverTxsSQL = "SELECT " + l2TransactionColumns + " FROM pool.transaction WHERE status = $1 AND received_at < $2 ORDER BY id"
+
+	return p.queryL2Transactions(ctx, recoverTxsSQL, types.TxStatusPending, receivedBefore)
+}
+
+// ClaimL2TransactionToRecover claims the pending tx with the id to be sent by the recovery sweep, if it's still pending
+// and it has not been updated since the updatedBefore time. The update time of the tx is set to now, so other instances
+// (or the next sweep) don't claim it again until it's stuck again. If leases are enabled the tx is only claimed if it's
+// not owned by other instances. Returns pgx.ErrNoRows if the tx can't be claimed
+func (p *PoolDB) ClaimL2TransactionToRecover(ctx context.Context, id uint64, updatedBefore time.Time) (*types.L2Transaction, error) {
+	const claimTxSQL = `
+		UPDATE pool.transaction SET updated_at = $4,
+			owner_id = CASE WHEN $5 THEN $6 ELSE owner_id END,
+			lease_expires_at = CASE WHEN $5 THEN $7 ELSE lease_expires_at END
+		WHERE id = $1 AND status = $2 AND updated_at < $3
+			AND (NOT $5 OR owner_id IS NULL OR owner_id = $6 OR lease_expires_at < $4)
+		RETURNING ` + l2TransactionColumns
+
+	now := time.Now()
+	return scanL2Transaction(p.db.QueryRow(ctx, claimTxSQL, id, types.TxStatusPending, updatedBefore, now, p.cfg.Lease.Enabled, p.ownerID,
+		now.Add(p.cfg.Lease.Duration.Duration)))
+}
+
+// GetL2TransactionsToSendByID returns the tx with the id if it's pending. If leases are enabled the tx is only returned
+// if it's not owned by other instances, and it's claimed for this instance
+func (p *PoolDB) GetL2TransactionsToSendByID(ctx context.Context, id uint64) ([]*types.L2Transaction, error) {
+	if p.cfg.Lease.Enabled {
+		return p.claimL2Transactions(ctx, "id = $1 AND status = $2", id, types.TxStatusPending)
+	}
+
+	const sendTxSQL = "SELECT " + l2TransactionColumns + " FROM pool.transaction WHERE id = $1 AND status = $2"
+
+	return p.queryL2Transactions(ctx, sendTxSQL, id, types.TxStatusPending)
+}
+
+// ListenL2TransactionNotifications listens for the notifications of txs that change to pending or resend status and
+// sends them to the notifications channel. It returns when the context is done or the connection fails
+func (p *PoolDB) ListenL2TransactionNotifications(ctx context.Context, notifications chan<- types.L2TransactionNotification) error {
+	conn, err := p.db.Acquire(ctx)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		_, _ = conn.Exec(context.Background(), "UNLISTEN *")
+		conn.Release()
+	}()
 
-	rows, err := p.db.Query(ctx, resendTxsSQL, status)
+	_, err = conn.Exec(ctx, "LISTEN "+l2TransactionChannel)
+	if err != nil {
+		return err
+	}
+
+	for {
+		n, err := conn.Conn().WaitForNotification(ctx)
+		if err != nil {
+			return err
+		}
+
+		var notification types.L2TransactionNotification
+		err = json.Unmarshal([]byte(n.Payload), &notification)
+		if err != nil {
+			log.Warnf("error decoding tx notification %s, error: %v", n.Payload, err)
+			continue
+		}
+
+		select {
+		case notifications <- notification:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
+
+// claimL2Transactions claims the lease of the txs that match the condition and are not owned by other instances (or
+// their lease has expired). Rows locked by other instances claiming at the same time are skipped
+func (p *PoolDB) claimL2Transactions(ctx context.Context, condition string, args ...interface{}) ([]*types.L2Transaction, error) {
+	n := len(args)
+	claimTxsSQL := fmt.Sprintf(`
+		UPDATE pool.transaction SET owner_id = $%[1]d, lease_expires_at = $%[2]d
+		WHERE id IN (
+			SELECT id FROM pool.transaction
+			WHERE %[4]s AND (owner_id IS NULL OR owner_id = $%[1]d OR lease_expires_at < $%[3]d)
+			ORDER BY id
+			FOR UPDATE SKIP LOCKED
+		)
+		RETURNING %[5]s
+	`, n+1, n+2, n+3, condition, l2TransactionColumns)
+
+	now := time.Now()
+	args = append(args, p.ownerID, now.Add(p.cfg.Lease.Duration.Duration), now)
+
+	return p.queryL2Transactions(ctx, claimTxsSQL, args...)
+}
+
+// queryL2Transactions runs a query that returns rows with the l2TransactionColumns
+func (p *PoolDB) queryL2Transactions(ctx context.Context, sql string, args ...interface{}) ([]*types.L2Transaction, error) {
+	rows, err := p.db.Query(ctx, sql, args...)
 	if err != nil {
 		return nil, err
 	}
 
 	defer rows.Close()
 
-	txs := make([]*types.L2Transaction, len(rows.RawValues()))
+	txs := []*types.L2Transaction{}
 	for rows.Next() {
-		tx := &types.L2Transaction{}
-
-		err := rows.Scan(&tx.Id, &tx.Hash, &tx.ReceivedAt, &tx.FromAddress, &tx.GasPrice, &tx.Nonce, &tx.Status, &tx.IP, &tx.Encoded, &tx.Decoded)
+		tx, err := scanL2Transaction(rows)
 		if err != nil {
 			return nil, err
 		}
@@ -64,28 +223,274 @@ func (p *PoolDB) GetL2TransactionsByStatus(ctx context.Context, status string) (
 		txs = append(txs, tx)
 	}
 
-	return txs, nil
+	return txs, rows.Err()
 }
 
-func (p *PoolDB) GetL2TransactionsToResend(ctx context.Context) ([]*types.L2Transaction, error) {
-	return p.GetL2TransactionsByStatus(ctx, types.TxStatusResend)
+// UpdateExpiredL2Transactions updates the sent txs that have reached their lifetime, counted from the time they were
+// received or, if fromLastSent is set, from the last time they were sent. The lifetime of each tx is the one set by the
+// client, up to the maxLifetime. The txs that have been resent less than maxResends times because they expired are
+// moved to resend status, so the sender sends them again. The rest are updated to expired and moved to the dead-letter
+// store. The txs in excludedIDs are skipped. It returns the number of expired and resent txs
+func (p *PoolDB) UpdateExpiredL2Transactions(ctx context.Context, maxLifetime time.Duration, fromLastSent bool, maxResends uint64, excludedIDs []uint64) (int64, int64, error) {
+	const updateExpiredSQL = `
+		WITH reached AS (
+			SELECT id, expiry_resends < $7 AS resend FROM pool.transaction
+			WHERE status = $3 AND %s < $1::TIMESTAMPTZ - LEAST(COALESCE(lifetime, $4::BIGINT), $4::BIGINT) * INTERVAL '1 second'
+			AND id <> ALL($10::BIGINT[])
+			FOR UPDATE
+		), resent AS (
+			UPDATE pool.transaction t SET updated_at = $1, status = $6, error = $8, expiry_resends = t.expiry_resends + 1
+			FROM reached r WHERE t.id = r.id AND r.resend
+			RETURNING t.id
+		), resent_errors AS (
+			INSERT INTO pool.transaction_error (tx_id, created_at, source, status, error)
+			SELECT id, $1, $9, $6, $8 FROM resent
+		), expired AS (
+			UPDATE pool.transaction t SET updated_at = $1, status = $2
+			FROM reached r WHERE t.id = r.id AND NOT r.resend
+			RETURNING t.id
+		), dead_letters AS (
+			INSERT INTO pool.dead_letter (tx_id, created_at, status, category)
+			SELECT id, $1, $2, $5 FROM expired
+			ON CONFLICT (tx_id) DO UPDATE
+			SET created_at = EXCLUDED.created_at, status = EXCLUDED.status, category = EXCLUDED.category, error = NULL, requeued_at = NULL
+		)
+		SELECT (SELECT COUNT(*) FROM expired), (SELECT COUNT(*) FROM resent)
+	`
+
+	since := "COALESCE(requeued_at, received_at)"
+	if fromLastSent {
+		since = "COALESCE(last_sent_at, requeued_at, received_at)"
+	}
+
+	var expired, resent int64
+	category := types.DeadLetterCategory(types.TxStatusExpired, "")
+	if excludedIDs == nil {
+		excludedIDs = []uint64{}
+	}
+	err := p.db.QueryRow(ctx, fmt.Sprintf(updateExpiredSQL, since), time.Now(), types.TxStatusExpired, types.TxStatusSent,
+		int64(maxLifetime.Seconds()), category, types.TxStatusResend, maxResends, expiredTxError, types.TxErrorSourceStatus, excludedIDs).Scan(&expired, &resent)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	return expired, resent, nil
 }
 
-func (p *PoolDB) GetL2TransactionsToMonitor(ctx context.Context) ([]*types.L2Transaction, error) {
-	return p.GetL2TransactionsByStatus(ctx, types.TxStatusSent)
+// RenewL2TransactionLeases extends the leases of the not finished txs owned by this instance
+func (p *PoolDB) RenewL2TransactionLeases(ctx context.Context) (int64, error) {
+	const renewLeasesSQL = "UPDATE pool.transaction SET lease_expires_at = $2 WHERE owner_id = $1 AND status IN ($3, $4, $5)"
+
+	result, err := p.db.Exec(ctx, renewLeasesSQL, p.ownerID, time.Now().Add(p.cfg.Lease.Duration.Duration), types.TxStatusPending, types.TxStatusSent, types.TxStatusResend)
+	if err != nil {
+		return 0, err
+	}
+
+	return result.RowsAffected(), nil
 }
 
-func (p *PoolDB) GetL2TransactionsToSend(ctx context.Context) ([]*types.L2Transaction, error) {
-	return p.GetL2TransactionsByStatus(ctx, types.TxStatusPending)
+// ReleaseL2TransactionLeases expires the leases owned by this instance, so other instances can claim the txs without
+// waiting for the lease duration
+func (p *PoolDB) ReleaseL2TransactionLeases(ctx context.Context) (int64, error) {
+	const releaseLeasesSQL = "UPDATE pool.transaction SET lease_expires_at = $2 WHERE owner_id = $1 AND status IN ($3, $4, $5)"
+
+	result, err := p.db.Exec(ctx, releaseLeasesSQL, p.ownerID, time.Now(), types.TxStatusPending, types.TxStatusSent, types.TxStatusResend)
+	if err != nil {
+		return 0, err
+	}
+
+	return result.RowsAffected(), nil
 }
 
+// StartLeaseHeartbeat renews periodically the leases owned by this instance, so they are not claimed by other
+// instances. It runs until the context is done
+func (p *PoolDB) StartLeaseHeartbeat(ctx context.Context) {
+	if !p.cfg.Lease.Enabled {
+		return
+	}
+
+	log.Infof("starting lease heartbeat for owner %s", p.ownerID)
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(p.cfg.Lease.HeartbeatInterval.Duration):
+		}
+
+		renewed, err := p.RenewL2TransactionLeases(ctx)
+		if err != nil {
+			log.Errorf("error renewing leases for owner %s, error: %v", p.ownerID, err)
+			continue
+		}
+		log.Debugf("renewed %d leases for owner %s", renewed, p.ownerID)
+	}
+}
+
+// UpdateL2TransactionStatus updates the status of the tx. The final statuses (confirmed and failed) can only be
+// overwritten by another final status, so a late invalid or sent status from the sender doesn't overwrite the receipt
+// status set by the monitor. The virtualized, verified and replaced statuses are never overwritten. The error is added
+// to the error history of the tx, and if the new status is a failure status the tx is moved to the dead-letter store
 func (p *PoolDB) UpdateL2TransactionStatus(ctx context.Context, id uint64, newStatus string, errorMsg string) error {
-	const updateStatusSQL = "UPDATE pool.transaction SET updated_at = $2, status = $3, error = $4 WHERE id = $1"
+	return p.updateL2TransactionStatus(ctx, id, newStatus, errorMsg, nil)
+}
+
+// updateL2TransactionStatus updates the status of the tx, recording the error in its error history, moving it to the
+// dead-letter store if the status is final and storing its receipt (if not nil)
+func (p *PoolDB) updateL2TransactionStatus(ctx context.Context, id uint64, newStatus string, errorMsg string, receipt *types.L2TransactionReceipt) error {
+	const updateStatusSQL = `
+		UPDATE pool.transaction SET updated_at = $2, status = $3, error = $4
+		WHERE id = $1 AND (status IS NULL OR status NOT IN ($5, $6, $7, $8, $9) OR ($3 IN ($5, $6) AND status IN ($5, $6)))
+	`
 
-	_, err := p.db.Exec(ctx, updateStatusSQL, id, time.Now(), newStatus, errorMsg)
+	if errorMsg == "" && !types.IsDeadLetterStatus(newStatus) && receipt == nil {
+		_, err := p.db.Exec(ctx, updateStatusSQL, id, time.Now(), newStatus, errorMsg, types.TxStatusConfirmed, types.TxStatusFailed,
+			types.TxStatusVirtualized, types.TxStatusVerified, types.TxStatusReplaced)
+		return err
+	}
+
+	dbTx, err := p.db.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = dbTx.Rollback(ctx) }()
+
+	now := time.Now()
+	result, err := dbTx.Exec(ctx, updateStatusSQL, id, now, newStatus, errorMsg, types.TxStatusConfirmed, types.TxStatusFailed,
+		types.TxStatusVirtualized, types.TxStatusVerified, types.TxStatusReplaced)
+	if err != nil {
+		return err
+	}
+	if result.RowsAffected() == 0 {
+		return nil
+	}
+
+	if errorMsg != "" {
+		err = addL2TransactionError(ctx, dbTx, id, now, types.TxErrorSourceStatus, newStatus, errorMsg)
+		if err != nil {
+			return err
+		}
+	}
+
+	if types.IsDeadLetterStatus(newStatus) {
+		err = addDeadLetter(ctx, dbTx, id, now, newStatus, errorMsg)
+		if err != nil {
+			return err
+		}
+	}
+
+	if receipt != nil {
+		err = addL2TransactionReceipt(ctx, dbTx, now, receipt)
+		if err != nil {
+			return err
+		}
+	}
+
+	return dbTx.Commit(ctx)
+}
+
+// UpdateL2TransactionSendAttempt records a new attempt to send the tx to the sequencer. The error returned by the
+// sequencer (if any) is added to the error history of the tx, and the persisted schedule of the monitor is cleared as
+// the tx is monitored again from the send
+func (p *PoolDB) UpdateL2TransactionSendAttempt(ctx context.Context, id uint64, sentAt time.Time, errorMsg string) error {
+	const updateSendAttemptSQL = `
+		WITH updated AS (
+			UPDATE pool.transaction
+			SET attempt_count = attempt_count + 1, first_sent_at = COALESCE(first_sent_at, $2), last_sent_at = $2, last_error = NULLIF($3, ''),
+				next_check_at = NULL
+			WHERE id = $1
+			RETURNING id
+		)
+		INSERT INTO pool.transaction_error (tx_id, created_at, source, error)
+		SELECT id, $2, $4, $3 FROM updated WHERE $3 <> ''
+	`
+
+	_, err := p.db.Exec(ctx, updateSendAttemptSQL, id, sentAt, errorMsg, types.TxErrorSourceSender)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// AddShadowSendResult stores the result of sending a tx to the primary and to the shadow sequencer
+func (p *PoolDB) AddShadowSendResult(ctx context.Context, result *types.ShadowSendResult) error {
+	const addShadowSendSQL = `
+		INSERT INTO pool.shadow_send (tx_id, hash, sent_at, primary_error, primary_latency_ms, shadow_url, shadow_error, shadow_latency_ms)
+		VALUES ($1, $2, $3, NULLIF($4, ''), $5, $6, NULLIF($7, ''), $8)
+	`
+
+	_, err := p.db.Exec(ctx, addShadowSendSQL, result.TxId, result.Hash, result.SentAt, result.PrimaryError,
+		result.PrimaryLatency.Milliseconds(), result.ShadowURL, result.ShadowError, result.ShadowLatency.Milliseconds())
 	if err != nil {
 		return err
 	}
 
 	return nil
 }
+
+// scanL2Transaction reads a L2 transaction from a row with the l2TransactionColumns
+func scanL2Transaction(row pgx.Row) (*types.L2Transaction, error) {
+	tx := &types.L2Transaction{}
+	var firstSentAt, lastSentAt, nextCheckAt, requeuedAt *time.Time
+	var lifetime int64
+
+	err := row.Scan(&tx.Id, &tx.Hash, &tx.ReceivedAt, &tx.FromAddress, &tx.GasPrice, &tx.Nonce, &tx.Status, &tx.IP, &tx.Encoded, &tx.Decoded,
+		&tx.AttemptCount, &firstSentAt, &lastSentAt, &tx.LastError, &lifetime, &tx.ExpiryResends,
+		&nextCheckAt, &tx.CheckCount, &tx.APIKey, &requeuedAt)
+	if err != nil {
+		return nil, err
+	}
+
+	if firstSentAt != nil {
+		tx.FirstSentAt = *firstSentAt
+	}
+	if lastSentAt != nil {
+		tx.LastSentAt = *lastSentAt
+	}
+	if nextCheckAt != nil {
+		tx.NextCheckAt = *nextCheckAt
+	}
+	if requeuedAt != nil {
+		tx.RequeuedAt = *requeuedAt
+	}
+	tx.Lifetime = time.Duration(lifetime) * time.Second
+
+	return tx, nil
+}
+
+// AdvisoryLock is a postgres session-level advisory lock held on a dedicated connection of the pool
+type AdvisoryLock struct {
+	conn *pgxpool.Conn
+	key  int64
+}
+
+// TryAdvisoryLock tries to get the session-level advisory lock for the key. If the lock is acquired, the connection
+// used is kept out of the pool until the lock is released, as the lock is released by postgres if the session ends
+func (p *PoolDB) TryAdvisoryLock(ctx context.Context, key int64) (*AdvisoryLock, error) {
+	conn, err := p.db.Acquire(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var locked bool
+	err = conn.QueryRow(ctx, "SELECT pg_try_advisory_lock($1)", key).Scan(&locked)
+	if err != nil || !locked {
+		conn.Release()
+		return nil, err
+	}
+
+	return &AdvisoryLock{conn: conn, key: key}, nil
+}
+
+// Ping checks the session holding the advisory lock is still alive
+func (l *AdvisoryLock) Ping(ctx context.Context) error {
+	return l.conn.Conn().Ping(ctx)
+}
+
+// Release releases the advisory lock and returns the connection to the pool
+func (l *AdvisoryLock) Release(ctx context.Context) error {
+	defer l.conn.Release()
+
+	_, err := l.conn.Exec(ctx, "SELECT pg_advisory_unlock($1)", l.key)
+	return err
+}
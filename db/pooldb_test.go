@@ -0,0 +1,198 @@
+package db
+
+import (
+	"context"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/0xPolygonHermez/zkevm-pool-manager/config/types"
+	poolTypes "github.com/0xPolygonHermez/zkevm-pool-manager/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newTestPoolDB connects to the pool database started with docker-compose (zkevm-pool-db), or to the host set in the
+// ZKEVM_POOL_DB_HOST env var, and runs the migrations. The test is skipped if the database is not reachable
+func newTestPoolDB(t *testing.T, lease LeaseConfig) *PoolDB {
+	host := os.Getenv("ZKEVM_POOL_DB_HOST")
+	if host == "" {
+		host = "localhost"
+	}
+	cfg := Config{
+		Name:     "pool_db",
+		User:     "pool_user",
+		Password: "pool_password",
+		Host:     host,
+		Port:     "5432",
+		MaxConns: 10,
+		Lease:    lease,
+	}
+
+	poolDB, err := NewPoolDB(cfg)
+	if err != nil {
+		t.Skipf("pool database not reachable, error: %v", err)
+	}
+	t.Cleanup(poolDB.Close)
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	if err := poolDB.db.Ping(ctx); err != nil {
+		t.Skipf("pool database not reachable, error: %v", err)
+	}
+	require.NoError(t, RunMigrationsUp(cfg, PoolMigrationName))
+
+	return poolDB
+}
+
+// resetTestPoolDB deletes all the txs of the pool database
+func resetTestPoolDB(t *testing.T, poolDB *PoolDB) {
+	_, err := poolDB.db.Exec(context.Background(), "DELETE FROM pool.transaction")
+	require.NoError(t, err)
+}
+
+// addTestL2Transaction adds a tx with the status to the pool database
+func addTestL2Transaction(t *testing.T, poolDB *PoolDB, status string) *poolTypes.L2Transaction {
+	l2Tx := &poolTypes.L2Transaction{
+		Hash:        "0x01",
+		ReceivedAt:  time.Now(),
+		FromAddress: "0xabc",
+		Status:      status,
+		Decoded:     "{}",
+	}
+
+	var err error
+	l2Tx.Id, err = poolDB.AddL2Transaction(context.Background(), l2Tx)
+	require.NoError(t, err)
+
+	return l2Tx
+}
+
+// ids returns the ids of the txs
+func ids(l2Txs []*poolTypes.L2Transaction) []uint64 {
+	ids := []uint64{}
+	for _, l2Tx := range l2Txs {
+		ids = append(ids, l2Tx.Id)
+	}
+	return ids
+}
+
+func TestL2TransactionLeases(t *testing.T) {
+	ctx := context.Background()
+	lease := LeaseConfig{Enabled: true, Duration: types.NewDuration(time.Second), HeartbeatInterval: types.NewDuration(100 * time.Millisecond)}
+
+	lease.OwnerID = "instance-a"
+	instanceA := newTestPoolDB(t, lease)
+	lease.OwnerID = "instance-b"
+	instanceB := newTestPoolDB(t, lease)
+	resetTestPoolDB(t, instanceA)
+
+	// The tx is owned by the instance that receives it
+	l2Tx := addTestL2Transaction(t, instanceA, poolTypes.TxStatusPending)
+
+	claimed, err := instanceB.GetL2TransactionsToSend(ctx)
+	require.NoError(t, err)
+	assert.Empty(t, claimed)
+	claimed, err = instanceA.GetL2TransactionsToSend(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, []uint64{l2Tx.Id}, ids(claimed))
+
+	// The renewed lease is not taken over after the initial lease duration
+	time.Sleep(600 * time.Millisecond)
+	renewed, err := instanceA.RenewL2TransactionLeases(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), renewed)
+	time.Sleep(600 * time.Millisecond)
+	claimed, err = instanceB.GetL2TransactionsToSend(ctx)
+	require.NoError(t, err)
+	assert.Empty(t, claimed)
+
+	// The released lease is claimed by other instance without waiting for the lease duration
+	released, err := instanceA.ReleaseL2TransactionLeases(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), released)
+	claimed, err = instanceB.GetL2TransactionsToSend(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, []uint64{l2Tx.Id}, ids(claimed))
+	claimed, err = instanceA.GetL2TransactionsToSend(ctx)
+	require.NoError(t, err)
+	assert.Empty(t, claimed)
+
+	// The lease of a stopped instance is taken over when it expires
+	time.Sleep(1100 * time.Millisecond)
+	claimed, err = instanceA.GetL2TransactionsToSend(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, []uint64{l2Tx.Id}, ids(claimed))
+}
+
+func TestListenL2TransactionNotifications(t *testing.T) {
+	poolDB := newTestPoolDB(t, LeaseConfig{})
+	resetTestPoolDB(t, poolDB)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	notifications := make(chan poolTypes.L2TransactionNotification)
+	done := make(chan error, 1)
+	go func() { done <- poolDB.ListenL2TransactionNotifications(ctx, notifications) }()
+	time.Sleep(100 * time.Millisecond)
+
+	// The tx inserted without leases has no owner
+	l2Tx := addTestL2Transaction(t, poolDB, poolTypes.TxStatusPending)
+	select {
+	case notification := <-notifications:
+		assert.Equal(t, poolTypes.L2TransactionNotification{Id: l2Tx.Id, Status: poolTypes.TxStatusPending}, notification)
+	case <-time.After(time.Second):
+		require.Fail(t, "tx notification not received")
+	}
+
+	// The listen returns when the context is done, even if nobody reads the notifications
+	addTestL2Transaction(t, poolDB, poolTypes.TxStatusPending)
+	time.Sleep(100 * time.Millisecond)
+	cancel()
+	select {
+	case err := <-done:
+		assert.ErrorIs(t, err, context.Canceled)
+	case <-time.After(time.Second):
+		require.Fail(t, "listen not stopped")
+	}
+}
+
+func TestUpdateL2TransactionsBatchStatus(t *testing.T) {
+	ctx := context.Background()
+	poolDB := newTestPoolDB(t, LeaseConfig{})
+	resetTestPoolDB(t, poolDB)
+
+	confirmed := addTestL2Transaction(t, poolDB, poolTypes.TxStatusConfirmed)
+	failed := addTestL2Transaction(t, poolDB, poolTypes.TxStatusFailed)
+	for _, id := range []uint64{confirmed.Id, failed.Id} {
+		_, err := poolDB.db.Exec(ctx, "INSERT INTO pool.receipt (tx_id, created_at, status, block_number, block_hash, tx_index, gas_used) VALUES ($1, NOW(), 1, 10, '0x0a', 0, 21000)", id)
+		require.NoError(t, err)
+	}
+
+	// The reverted txs are tracked too
+	blocks, err := poolDB.GetL2BlocksWithoutBatchNumber(ctx, 10)
+	require.NoError(t, err)
+	assert.Equal(t, []uint64{10}, blocks)
+	require.NoError(t, poolDB.UpdateL2BlockBatchNumber(ctx, 10, 5))
+
+	virtualized, verified, err := poolDB.UpdateL2TransactionsBatchStatus(ctx, 5, 4)
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), virtualized)
+	assert.Zero(t, verified)
+	virtualized, verified, err = poolDB.UpdateL2TransactionsBatchStatus(ctx, 5, 5)
+	require.NoError(t, err)
+	assert.Zero(t, virtualized)
+	assert.Equal(t, int64(2), verified)
+
+	// The failed tx keeps its status, only the times are set
+	l2Txs, err := poolDB.GetL2TransactionsByStatus(ctx, poolTypes.TxStatusFailed)
+	require.NoError(t, err)
+	assert.Equal(t, []uint64{failed.Id}, ids(l2Txs))
+	l2Txs, err = poolDB.GetL2TransactionsByStatus(ctx, poolTypes.TxStatusVerified)
+	require.NoError(t, err)
+	assert.Equal(t, []uint64{confirmed.Id}, ids(l2Txs))
+	var virtualizedAt, verifiedAt *time.Time
+	require.NoError(t, poolDB.db.QueryRow(ctx, "SELECT virtualized_at, verified_at FROM pool.transaction WHERE id = $1", failed.Id).Scan(&virtualizedAt, &verifiedAt))
+	assert.NotNil(t, virtualizedAt)
+	assert.NotNil(t, verifiedAt)
+}
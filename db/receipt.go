@@ -0,0 +1,63 @@
+package db
+
+import (
+	"context"
+	"math/big"
+	"time"
+
+	"github.com/0xPolygonHermez/zkevm-pool-manager/types"
+	"github.com/jackc/pgx/v4"
+)
+
+// UpdateL2TransactionReceipt updates the status of the tx with the result of its receipt and stores the receipt details.
+// The receipt is not stored if the status of the tx can't be updated
+func (p *PoolDB) UpdateL2TransactionReceipt(ctx context.Context, id uint64, newStatus string, receipt *types.L2TransactionReceipt) error {
+	return p.updateL2TransactionStatus(ctx, id, newStatus, "", receipt)
+}
+
+// GetL2TransactionReceipt returns the receipt stored for the tx with the hash. If the tx has been added more than once
+// to the pool, the last receipt is returned. Returns pgx.ErrNoRows if there is no receipt for the tx
+func (p *PoolDB) GetL2TransactionReceipt(ctx context.Context, hash string) (*types.L2TransactionReceipt, error) {
+	const getReceiptSQL = `
+		SELECT r.tx_id, r.created_at, r.status, r.block_number, r.block_hash, r.tx_index, r.gas_used,
+			r.effective_gas_price::VARCHAR, COALESCE(r.contract_address, ''), r.logs_bloom
+		FROM pool.receipt r JOIN pool.transaction t ON t.id = r.tx_id
+		WHERE t.hash = $1 ORDER BY r.created_at DESC LIMIT 1
+	`
+
+	receipt := &types.L2TransactionReceipt{}
+	var effectiveGasPrice *string
+	err := p.db.QueryRow(ctx, getReceiptSQL, hash).Scan(&receipt.TxId, &receipt.CreatedAt, &receipt.Status, &receipt.BlockNumber,
+		&receipt.BlockHash, &receipt.TxIndex, &receipt.GasUsed, &effectiveGasPrice, &receipt.ContractAddress, &receipt.LogsBloom)
+	if err != nil {
+		return nil, err
+	}
+
+	if effectiveGasPrice != nil {
+		receipt.EffectiveGasPrice, _ = new(big.Int).SetString(*effectiveGasPrice, 10)
+	}
+
+	return receipt, nil
+}
+
+// addL2TransactionReceipt stores the receipt of the tx. If the tx already has a receipt it's replaced
+func addL2TransactionReceipt(ctx context.Context, dbTx pgx.Tx, createdAt time.Time, receipt *types.L2TransactionReceipt) error {
+	const addReceiptSQL = `
+		INSERT INTO pool.receipt (tx_id, created_at, status, block_number, block_hash, tx_index, gas_used, effective_gas_price, contract_address, logs_bloom)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8::NUMERIC, NULLIF($9, ''), $10)
+		ON CONFLICT (tx_id) DO UPDATE
+		SET created_at = EXCLUDED.created_at, status = EXCLUDED.status, block_number = EXCLUDED.block_number, block_hash = EXCLUDED.block_hash,
+			tx_index = EXCLUDED.tx_index, gas_used = EXCLUDED.gas_used, effective_gas_price = EXCLUDED.effective_gas_price,
+			contract_address = EXCLUDED.contract_address, logs_bloom = EXCLUDED.logs_bloom
+	`
+
+	var effectiveGasPrice *string
+	if receipt.EffectiveGasPrice != nil {
+		price := receipt.EffectiveGasPrice.String()
+		effectiveGasPrice = &price
+	}
+
+	_, err := dbTx.Exec(ctx, addReceiptSQL, receipt.TxId, createdAt, receipt.Status, receipt.BlockNumber, receipt.BlockHash, receipt.TxIndex,
+		receipt.GasUsed, effectiveGasPrice, receipt.ContractAddress, receipt.LogsBloom)
+	return err
+}
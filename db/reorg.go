@@ -0,0 +1,62 @@
+package db
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/0xPolygonHermez/zkevm-pool-manager/types"
+)
+
+// GetL2BlocksToCheckReorg returns the L2 blocks, from the fromBlock, that include confirmed or failed txs, lowest first
+func (p *PoolDB) GetL2BlocksToCheckReorg(ctx context.Context, fromBlock uint64) ([]*types.L2Block, error) {
+	const getBlocksSQL = `
+		SELECT DISTINCT r.block_number, r.block_hash FROM pool.receipt r JOIN pool.transaction t ON t.id = r.tx_id
+		WHERE r.block_number >= $1 AND t.status IN ($2, $3)
+		ORDER BY r.block_number
+	`
+
+	rows, err := p.db.Query(ctx, getBlocksSQL, fromBlock, types.TxStatusConfirmed, types.TxStatusFailed)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	blocks := []*types.L2Block{}
+	for rows.Next() {
+		block := &types.L2Block{}
+		if err := rows.Scan(&block.Number, &block.Hash); err != nil {
+			return nil, err
+		}
+		blocks = append(blocks, block)
+	}
+
+	return blocks, rows.Err()
+}
+
+// ReorgL2Transactions moves the confirmed and failed txs included in the reorged L2 block back to the newStatus (sent
+// or resend), recording a reorg event for each tx. Their receipts are deleted, and the failed txs are removed from the
+// dead-letter store. The canonicalHash is the hash of the block with the same number in the L2 node, empty if the
+// block doesn't exist anymore. It returns the reorged txs
+func (p *PoolDB) ReorgL2Transactions(ctx context.Context, block *types.L2Block, canonicalHash string, newStatus string) ([]*types.L2Transaction, error) {
+	reorgSQL := fmt.Sprintf(`
+		WITH reorged AS (
+			SELECT t.id AS tx_id, t.status AS previous_status FROM pool.transaction t JOIN pool.receipt r ON r.tx_id = t.id
+			WHERE r.block_number = $3 AND r.block_hash = $4 AND t.status IN ($5, $6)
+			FOR UPDATE OF t
+		), events AS (
+			INSERT INTO pool.reorg (tx_id, detected_at, block_number, block_hash, canonical_hash, previous_status, new_status)
+			SELECT tx_id, $1, $3, $4, NULLIF($7, ''), previous_status, $2 FROM reorged
+		), receipts AS (
+			DELETE FROM pool.receipt WHERE tx_id IN (SELECT tx_id FROM reorged)
+		), dead_letters AS (
+			DELETE FROM pool.dead_letter WHERE tx_id IN (SELECT tx_id FROM reorged)
+		)
+		UPDATE pool.transaction SET updated_at = $1, status = $2, error = NULL
+		FROM reorged WHERE id = reorged.tx_id
+		RETURNING %s
+	`, l2TransactionColumns)
+
+	return p.queryL2Transactions(ctx, reorgSQL, time.Now(), newStatus, block.Number, block.Hash, types.TxStatusConfirmed,
+		types.TxStatusFailed, canonicalHash)
+}
@@ -0,0 +1,36 @@
+package db
+
+import (
+	"context"
+	"time"
+
+	"github.com/0xPolygonHermez/zkevm-pool-manager/types"
+	"github.com/jackc/pgx/v4"
+)
+
+// UpdateL2TransactionReplaced updates to replaced the status of the sent tx whose nonce has been used by another tx
+// of the account. The winning tx is searched in the pool database among the txs with the same account and nonce that
+// have a receipt. It returns the hash of the winning tx, or an empty string if it's not found. The status is only
+// updated if the tx is still sent
+func (p *PoolDB) UpdateL2TransactionReplaced(ctx context.Context, id uint64) (string, error) {
+	const updateReplacedSQL = `
+		UPDATE pool.transaction t SET updated_at = $2, status = $3, replaced_by = (
+			SELECT w.hash FROM pool.transaction w
+			WHERE w.from_address = t.from_address AND w.nonce = t.nonce AND w.id <> t.id AND w.status IN ($5, $6, $7, $8)
+			ORDER BY w.id DESC LIMIT 1
+		)
+		WHERE t.id = $1 AND t.status = $4
+		RETURNING t.replaced_by
+	`
+
+	var replacedBy *string
+	err := p.db.QueryRow(ctx, updateReplacedSQL, id, time.Now(), types.TxStatusReplaced, types.TxStatusSent, types.TxStatusConfirmed,
+		types.TxStatusFailed, types.TxStatusVirtualized, types.TxStatusVerified).Scan(&replacedBy)
+	if err == pgx.ErrNoRows {
+		return "", nil
+	} else if err != nil || replacedBy == nil {
+		return "", err
+	}
+
+	return *replacedBy, nil
+}
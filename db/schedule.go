@@ -0,0 +1,34 @@
+package db
+
+import (
+	"context"
+	"time"
+
+	"github.com/0xPolygonHermez/zkevm-pool-manager/types"
+)
+
+// UpdateL2TransactionsNextCheck persists the schedule of the next check of the receipt of the monitored txs in a
+// single statement. Only the txs that are still sent are updated. It returns the number of updated txs
+func (p *PoolDB) UpdateL2TransactionsNextCheck(ctx context.Context, checks []types.L2TransactionCheck) (int64, error) {
+	const updateNextCheckSQL = `
+		UPDATE pool.transaction t SET next_check_at = c.next_check_at, check_count = c.check_count
+		FROM UNNEST($1::BIGINT[], $2::TIMESTAMPTZ[], $3::BIGINT[]) AS c(id, next_check_at, check_count)
+		WHERE t.id = c.id AND t.status = $4
+	`
+
+	ids := make([]int64, len(checks))
+	nextCheckAts := make([]time.Time, len(checks))
+	checkCounts := make([]int64, len(checks))
+	for i, check := range checks {
+		ids[i] = int64(check.TxId)
+		nextCheckAts[i] = check.NextCheckAt
+		checkCounts[i] = int64(check.CheckCount)
+	}
+
+	result, err := p.db.Exec(ctx, updateNextCheckSQL, ids, nextCheckAts, checkCounts, types.TxStatusSent)
+	if err != nil {
+		return 0, err
+	}
+
+	return result.RowsAffected(), nil
+}
@@ -0,0 +1,15 @@
+package leader
+
+import "github.com/0xPolygonHermez/zkevm-pool-manager/config/types"
+
+// Config for pool-manager leader election
+type Config struct {
+	// Enabled defines if the singleton background loops only run in the pool-manager instance elected as leader
+	Enabled bool `mapstructure:"Enabled"`
+
+	// LockKey is the key of the postgres advisory lock used to elect the leader
+	LockKey int64 `mapstructure:"LockKey"`
+
+	// CheckInterval is the time between attempts to get the leadership, and between checks that it's still held
+	CheckInterval types.Duration `mapstructure:"CheckInterval"`
+}
@@ -0,0 +1,11 @@
+package leader
+
+import (
+	"context"
+
+	"github.com/0xPolygonHermez/zkevm-pool-manager/db"
+)
+
+type poolDBInterface interface {
+	TryAdvisoryLock(ctx context.Context, key int64) (*db.AdvisoryLock, error)
+}
@@ -0,0 +1,190 @@
+package leader
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/0xPolygonHermez/zkevm-pool-manager/log"
+)
+
+// Elector elects the pool-manager instance that runs the singleton background loops using a postgres advisory lock.
+// If the leader dies its session ends, postgres releases the lock and another instance gets the leadership
+type Elector struct {
+	cfg     Config
+	lock    lockInterface
+	since   time.Time
+	stopped bool
+	// onAcquired are the functions called each time the instance gets the leadership
+	onAcquired []func()
+	mutex      sync.RWMutex
+	// tryLock tries to get the leader lock, it returns a nil lock if the lock is held by other instance
+	tryLock func(ctx context.Context, key int64) (lockInterface, error)
+}
+
+// Status is the leadership status of the pool-manager instance
+type Status struct {
+	Enabled  bool      `json:"enabled"`
+	IsLeader bool      `json:"isLeader"`
+	Since    time.Time `json:"since,omitempty"`
+}
+
+type lockInterface interface {
+	Ping(ctx context.Context) error
+	Release(ctx context.Context) error
+}
+
+// NewElector creates a new leader elector
+func NewElector(cfg Config, poolDB poolDBInterface) *Elector {
+	return &Elector{
+		cfg: cfg,
+		tryLock: func(ctx context.Context, key int64) (lockInterface, error) {
+			lock, err := poolDB.TryAdvisoryLock(ctx, key)
+			if lock == nil {
+				return nil, err
+			}
+			return lock, err
+		},
+	}
+}
+
+// OnAcquired registers a function that is called each time the instance gets the leadership, to run the work that
+// only the leader does when it takes over. If the instance is already the leader (or leader election is disabled) the
+// function is called right away. The function is called from the leadership check, so it must not block
+func (e *Elector) OnAcquired(fn func()) {
+	e.mutex.Lock()
+	e.onAcquired = append(e.onAcquired, fn)
+	isLeader := !e.cfg.Enabled || e.lock != nil
+	e.mutex.Unlock()
+
+	if isLeader {
+		fn()
+	}
+}
+
+// Start tries to get the leadership and keeps checking it periodically in background until the context is done
+func (e *Elector) Start(ctx context.Context) {
+	if !e.cfg.Enabled {
+		log.Infof("leader election disabled, running as leader")
+		return
+	}
+
+	e.checkLeadership()
+
+	go func() {
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-time.After(e.cfg.CheckInterval.Duration):
+			}
+			e.checkLeadership()
+		}
+	}()
+}
+
+// Stop releases the leadership, so another instance can get it without waiting for this instance session to end, and
+// stops trying to get it
+func (e *Elector) Stop(ctx context.Context) error {
+	e.mutex.Lock()
+	lock := e.lock
+	e.lock = nil
+	e.since = time.Time{}
+	e.stopped = true
+	e.mutex.Unlock()
+
+	if lock == nil {
+		return nil
+	}
+
+	if err := lock.Release(ctx); err != nil {
+		return err
+	}
+
+	log.Infof("leadership released")
+	return nil
+}
+
+// IsLeader returns true if the instance is the leader. If leader election is disabled the instance is always the leader
+func (e *Elector) IsLeader() bool {
+	if !e.cfg.Enabled {
+		return true
+	}
+
+	e.mutex.RLock()
+	defer e.mutex.RUnlock()
+
+	return e.lock != nil
+}
+
+// Status returns the leadership status of the instance
+func (e *Elector) Status() Status {
+	e.mutex.RLock()
+	defer e.mutex.RUnlock()
+
+	return Status{
+		Enabled:  e.cfg.Enabled,
+		IsLeader: !e.cfg.Enabled || e.lock != nil,
+		Since:    e.since,
+	}
+}
+
+// checkLeadership tries to get the leadership if the instance is a follower, or checks the session holding the lock is
+// still alive if the instance is the leader. The mutex is not held during the calls to the pool db, so IsLeader is not
+// blocked by them. When the leadership is acquired the OnAcquired functions are called
+func (e *Elector) checkLeadership() {
+	ctx, cancel := context.WithTimeout(context.Background(), e.cfg.CheckInterval.Duration)
+	defer cancel()
+
+	e.mutex.RLock()
+	lock, stopped := e.lock, e.stopped
+	e.mutex.RUnlock()
+
+	if stopped {
+		return
+	}
+
+	if lock != nil {
+		err := lock.Ping(ctx)
+		if err == nil {
+			return
+		}
+		log.Warnf("leadership lost, error checking leader lock session: %v", err)
+
+		e.mutex.Lock()
+		if e.lock == lock {
+			e.lock = nil
+			e.since = time.Time{}
+		}
+		e.mutex.Unlock()
+
+		_ = lock.Release(ctx)
+		return
+	}
+
+	lock, err := e.tryLock(ctx, e.cfg.LockKey)
+	if err != nil {
+		log.Errorf("error trying to get the leader lock, error: %v", err)
+		return
+	}
+	if lock == nil {
+		return
+	}
+
+	e.mutex.Lock()
+	if e.stopped {
+		// The elector has been stopped while the lock was acquired
+		e.mutex.Unlock()
+		_ = lock.Release(ctx)
+		return
+	}
+	e.lock = lock
+	e.since = time.Now()
+	onAcquired := append([]func(){}, e.onAcquired...)
+	e.mutex.Unlock()
+
+	log.Infof("leadership acquired")
+	for _, fn := range onAcquired {
+		fn()
+	}
+}
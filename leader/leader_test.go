@@ -0,0 +1,146 @@
+package leader
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/0xPolygonHermez/zkevm-pool-manager/config/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeLocks is an in-memory advisory lock shared by the electors of the test, like the pool database
+type fakeLocks struct {
+	holder *fakeLock
+	mutex  sync.Mutex
+}
+
+// fakeLock is the lock held by an elector. Its session can be killed to simulate the connection is lost
+type fakeLock struct {
+	locks  *fakeLocks
+	killed atomic.Bool
+}
+
+func (l *fakeLocks) tryLock(ctx context.Context, key int64) (lockInterface, error) {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	if l.holder != nil {
+		return nil, nil
+	}
+	l.holder = &fakeLock{locks: l}
+	return l.holder, nil
+}
+
+func (l *fakeLocks) current() *fakeLock {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+	return l.holder
+}
+
+func (l *fakeLock) Ping(ctx context.Context) error {
+	if l.killed.Load() {
+		return errors.New("connection closed")
+	}
+	return nil
+}
+
+func (l *fakeLock) Release(ctx context.Context) error {
+	l.locks.mutex.Lock()
+	defer l.locks.mutex.Unlock()
+
+	if l.locks.holder == l {
+		l.locks.holder = nil
+	}
+	return nil
+}
+
+// kill ends the session holding the lock, then postgres releases the lock
+func (l *fakeLock) kill() {
+	l.killed.Store(true)
+	_ = l.Release(context.Background())
+}
+
+// newTestElector creates an elector using the locks and counts the times it gets the leadership
+func newTestElector(t *testing.T, locks *fakeLocks) (*Elector, *atomic.Int32) {
+	e := NewElector(Config{Enabled: true, CheckInterval: types.NewDuration(10 * time.Millisecond)}, nil)
+	e.tryLock = locks.tryLock
+
+	acquired := &atomic.Int32{}
+	e.OnAcquired(func() { acquired.Add(1) })
+
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+	e.Start(ctx)
+
+	return e, acquired
+}
+
+func TestElectorDisabled(t *testing.T) {
+	e := NewElector(Config{}, nil)
+	e.Start(context.Background())
+
+	called := false
+	e.OnAcquired(func() { called = true })
+
+	assert.True(t, e.IsLeader())
+	assert.True(t, called)
+}
+
+func TestElectorAcquire(t *testing.T) {
+	locks := &fakeLocks{}
+	leader, leaderAcquired := newTestElector(t, locks)
+	follower, followerAcquired := newTestElector(t, locks)
+
+	assert.True(t, leader.IsLeader())
+	assert.False(t, leader.Status().Since.IsZero())
+	assert.Equal(t, int32(1), leaderAcquired.Load())
+
+	// The follower keeps trying without getting the leadership
+	time.Sleep(50 * time.Millisecond)
+	assert.False(t, follower.IsLeader())
+	assert.Equal(t, int32(0), followerAcquired.Load())
+	assert.Equal(t, int32(1), leaderAcquired.Load())
+
+	// A function registered by the leader is called right away
+	called := false
+	leader.OnAcquired(func() { called = true })
+	assert.True(t, called)
+}
+
+func TestElectorLose(t *testing.T) {
+	locks := &fakeLocks{}
+	e, acquired := newTestElector(t, locks)
+	require.True(t, e.IsLeader())
+
+	// The session holding the lock ends, the leadership is lost and acquired again with a new session
+	lock := locks.current()
+	lock.kill()
+	require.Eventually(t, func() bool { return acquired.Load() == 2 }, time.Second, 5*time.Millisecond)
+	assert.True(t, e.IsLeader())
+	assert.NotSame(t, lock, locks.current())
+}
+
+func TestElectorHandover(t *testing.T) {
+	locks := &fakeLocks{}
+	leader, _ := newTestElector(t, locks)
+	follower, followerAcquired := newTestElector(t, locks)
+	require.True(t, leader.IsLeader())
+	require.False(t, follower.IsLeader())
+
+	// The leader stops and releases the lock, the follower gets the leadership and runs the leader work
+	require.NoError(t, leader.Stop(context.Background()))
+	assert.False(t, leader.IsLeader())
+	require.Eventually(t, follower.IsLeader, time.Second, 5*time.Millisecond)
+	assert.Equal(t, int32(1), followerAcquired.Load())
+
+	// The stopped elector doesn't get the leadership again
+	require.NoError(t, follower.Stop(context.Background()))
+	time.Sleep(50 * time.Millisecond)
+	assert.False(t, leader.IsLeader())
+	assert.Nil(t, locks.current())
+}
@@ -0,0 +1,41 @@
+package limiter
+
+import "github.com/0xPolygonHermez/zkevm-pool-manager/config/types"
+
+// Config for the limits of the outbound calls to a RPC node
+type Config struct {
+	// RequestsPerSecond is the max rate of requests to the RPC node, each request of a batch call counts as one (0 = no limit)
+	RequestsPerSecond float64 `mapstructure:"RequestsPerSecond"`
+
+	// Burst is the max number of calls that can be done at once above the RequestsPerSecond rate
+	Burst int `mapstructure:"Burst"`
+
+	// Adaptive is the configuration of the adaptive concurrency of the calls to the RPC node
+	Adaptive AdaptiveConfig `mapstructure:"Adaptive"`
+}
+
+// AdaptiveConfig for the adaptive concurrency (AIMD) of the calls to the RPC node. The number of concurrent calls is
+// increased by one after each window of calls without overload, and multiplied by DecreaseFactor after a window with
+// an error rate or an average latency above the thresholds
+type AdaptiveConfig struct {
+	// Enabled defines if the number of concurrent calls is adapted to the error rate and latency of the RPC node
+	Enabled bool `mapstructure:"Enabled"`
+
+	// MinConcurrency is the min number of concurrent calls
+	MinConcurrency uint16 `mapstructure:"MinConcurrency"`
+
+	// MaxConcurrency is the max number of concurrent calls, it's also the initial number of concurrent calls
+	MaxConcurrency uint16 `mapstructure:"MaxConcurrency"`
+
+	// Window is the number of calls used to compute the error rate and the average latency
+	Window uint16 `mapstructure:"Window"`
+
+	// ErrorRateThreshold is the rate of failed calls (0-1) in a window above which the concurrency is decreased
+	ErrorRateThreshold float64 `mapstructure:"ErrorRateThreshold"`
+
+	// LatencyThreshold is the average latency of the calls in a window above which the concurrency is decreased (0 = not used)
+	LatencyThreshold types.Duration `mapstructure:"LatencyThreshold"`
+
+	// DecreaseFactor is the factor (0-1) the concurrency is multiplied by when it's decreased
+	DecreaseFactor float64 `mapstructure:"DecreaseFactor"`
+}
@@ -0,0 +1,205 @@
+package limiter
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/0xPolygonHermez/zkevm-pool-manager/log"
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/rpc"
+	"golang.org/x/time/rate"
+)
+
+// Limiter limits the outbound calls to a RPC node using a token bucket and, optionally, an adaptive concurrency limit.
+// A call takes a token for each of its requests, so a batch call is limited by the number of requests it contains
+type Limiter struct {
+	name   string
+	cfg    Config
+	bucket *rate.Limiter
+	// limit is the current max number of concurrent calls, inUse the number of calls in progress
+	limit int
+	inUse int
+	// calls, failed and latency are the results of the calls in the current adaptive window
+	calls   int
+	failed  int
+	latency time.Duration
+	cond    *sync.Cond
+}
+
+// Status is the status of a limiter
+type Status struct {
+	Limit int `json:"limit"`
+	InUse int `json:"inUse"`
+}
+
+// NewLimiter creates a new limiter
+func NewLimiter(name string, cfg Config) *Limiter {
+	l := &Limiter{
+		name: name,
+		cfg:  cfg,
+		cond: sync.NewCond(&sync.Mutex{}),
+	}
+
+	if cfg.RequestsPerSecond > 0 {
+		burst := cfg.Burst
+		if burst <= 0 {
+			burst = 1
+		}
+		l.bucket = rate.NewLimiter(rate.Limit(cfg.RequestsPerSecond), burst)
+	}
+
+	if cfg.Adaptive.Enabled {
+		l.limit = int(cfg.Adaptive.MaxConcurrency)
+	}
+
+	return l
+}
+
+// Acquire waits until a call to the RPC node is allowed or the context is done. Each successful Acquire must be
+// followed by a Release with the result of the call
+func (l *Limiter) Acquire(ctx context.Context) error {
+	return l.AcquireN(ctx, 1)
+}
+
+// AcquireN waits until a call to the RPC node with n requests (i.e. a batch call) is allowed or the context is done.
+// The call takes a single concurrency slot and n tokens of the bucket. Each successful AcquireN must be followed by a
+// Release with the result of the call
+func (l *Limiter) AcquireN(ctx context.Context, n int) error {
+	if l.cfg.Adaptive.Enabled {
+		if err := l.acquireSlot(ctx); err != nil {
+			return err
+		}
+	}
+
+	if l.bucket != nil {
+		// The bucket can't give more tokens than its burst at once, so they are taken in chunks
+		for n > 0 {
+			tokens := min(n, l.bucket.Burst())
+			if err := l.bucket.WaitN(ctx, tokens); err != nil {
+				l.Cancel()
+				return err
+			}
+			n -= tokens
+		}
+	}
+
+	return nil
+}
+
+// TryAcquireToken takes a token of the bucket for a request added to a call already allowed by Acquire (i.e. a batch
+// call), only if it's available right away. Returns false if the bucket is empty
+func (l *Limiter) TryAcquireToken() bool {
+	if l.bucket == nil {
+		return true
+	}
+	return l.bucket.Allow()
+}
+
+// Release records the result of a call allowed by Acquire. The call is considered overloaded if it failed with an
+// error not returned by the RPC node itself
+func (l *Limiter) Release(latency time.Duration, err error) {
+	if !l.cfg.Adaptive.Enabled {
+		return
+	}
+
+	l.cond.L.Lock()
+	defer l.cond.L.Unlock()
+
+	l.inUse--
+	l.calls++
+	l.latency += latency
+	if IsOverloadError(err) {
+		l.failed++
+	}
+
+	if l.calls >= int(l.cfg.Adaptive.Window) {
+		l.adapt()
+	}
+
+	l.cond.Broadcast()
+}
+
+// Status returns the status of the limiter
+func (l *Limiter) Status() Status {
+	l.cond.L.Lock()
+	defer l.cond.L.Unlock()
+
+	return Status{Limit: l.limit, InUse: l.inUse}
+}
+
+// adapt updates the concurrency limit with the results of the current window and starts a new window
+func (l *Limiter) adapt() {
+	errorRate := float64(l.failed) / float64(l.calls)
+	avgLatency := l.latency / time.Duration(l.calls)
+	l.calls, l.failed, l.latency = 0, 0, 0
+
+	overloaded := errorRate > l.cfg.Adaptive.ErrorRateThreshold ||
+		(l.cfg.Adaptive.LatencyThreshold.Duration > 0 && avgLatency > l.cfg.Adaptive.LatencyThreshold.Duration)
+
+	limit := l.limit
+	if overloaded {
+		limit = int(float64(l.limit) * l.cfg.Adaptive.DecreaseFactor)
+		if limit < int(l.cfg.Adaptive.MinConcurrency) {
+			limit = int(l.cfg.Adaptive.MinConcurrency)
+		}
+		if limit < 1 {
+			limit = 1
+		}
+	} else if l.limit < int(l.cfg.Adaptive.MaxConcurrency) {
+		limit++
+	}
+
+	if limit != l.limit {
+		log.Infof("%s concurrency limit changed from %d to %d, error rate: %.2f, avg latency: %v", l.name, l.limit, limit, errorRate, avgLatency)
+		l.limit = limit
+	}
+}
+
+// acquireSlot waits until the number of concurrent calls is below the concurrency limit
+func (l *Limiter) acquireSlot(ctx context.Context) error {
+	stop := context.AfterFunc(ctx, func() {
+		l.cond.L.Lock()
+		defer l.cond.L.Unlock()
+		l.cond.Broadcast()
+	})
+	defer stop()
+
+	l.cond.L.Lock()
+	defer l.cond.L.Unlock()
+
+	for l.inUse >= l.limit {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		l.cond.Wait()
+	}
+	l.inUse++
+
+	return nil
+}
+
+// Cancel releases a call allowed by Acquire that has not been done, without recording a result
+func (l *Limiter) Cancel() {
+	if !l.cfg.Adaptive.Enabled {
+		return
+	}
+
+	l.cond.L.Lock()
+	defer l.cond.L.Unlock()
+
+	l.inUse--
+	l.cond.Broadcast()
+}
+
+// IsOverloadError returns true if the error is not returned by the RPC node itself (e.g. a timeout, a connection error
+// or a HTTP error), so the RPC node may be overloaded
+func IsOverloadError(err error) bool {
+	if err == nil || errors.Is(err, ethereum.NotFound) {
+		return false
+	}
+
+	var rpcErr rpc.Error
+	return !errors.As(err, &rpcErr)
+}
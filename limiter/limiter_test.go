@@ -0,0 +1,117 @@
+package limiter
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/0xPolygonHermez/zkevm-pool-manager/config/types"
+	"github.com/ethereum/go-ethereum"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// rpcError is an error returned by the RPC node
+type rpcError struct {
+	code    int
+	message string
+}
+
+func (e *rpcError) Error() string  { return e.message }
+func (e *rpcError) ErrorCode() int { return e.code }
+
+func TestLimiterRate(t *testing.T) {
+	l := NewLimiter("test", Config{RequestsPerSecond: 20, Burst: 1})
+
+	start := time.Now()
+	for i := 0; i < 5; i++ {
+		require.NoError(t, l.Acquire(context.Background()))
+		l.Release(0, nil)
+	}
+
+	// The first call uses the burst, the next 4 wait 50ms each
+	assert.GreaterOrEqual(t, time.Since(start), 150*time.Millisecond)
+}
+
+func TestLimiterAdaptive(t *testing.T) {
+	l := NewLimiter("test", Config{Adaptive: AdaptiveConfig{
+		Enabled:            true,
+		MinConcurrency:     1,
+		MaxConcurrency:     4,
+		Window:             2,
+		ErrorRateThreshold: 0.4,
+		LatencyThreshold:   types.NewDuration(time.Second),
+		DecreaseFactor:     0.5,
+	}})
+	assert.Equal(t, 4, l.Status().Limit)
+
+	call := func(latency time.Duration, err error) {
+		require.NoError(t, l.Acquire(context.Background()))
+		l.Release(latency, err)
+	}
+
+	// A window with overload errors halves the limit
+	call(0, errors.New("connection refused"))
+	call(0, nil)
+	assert.Equal(t, 2, l.Status().Limit)
+
+	// A window with high latency halves the limit, down to MinConcurrency
+	call(2*time.Second, nil)
+	call(2*time.Second, nil)
+	assert.Equal(t, 1, l.Status().Limit)
+	call(2*time.Second, nil)
+	call(2*time.Second, nil)
+	assert.Equal(t, 1, l.Status().Limit)
+
+	// Errors returned by the node itself are not overload, the limit is increased by one per window
+	call(0, &rpcError{code: -32000, message: "nonce too low"})
+	call(0, ethereum.NotFound)
+	assert.Equal(t, 2, l.Status().Limit)
+}
+
+func TestLimiterAdaptiveWait(t *testing.T) {
+	l := NewLimiter("test", Config{Adaptive: AdaptiveConfig{Enabled: true, MinConcurrency: 1, MaxConcurrency: 1, Window: 10, DecreaseFactor: 0.5}})
+
+	require.NoError(t, l.Acquire(context.Background()))
+
+	// The concurrency limit is reached, so the next call waits until the context is done
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	assert.ErrorIs(t, l.Acquire(ctx), context.DeadlineExceeded)
+
+	// Once the call in progress is released the next call is allowed
+	done := make(chan error)
+	go func() { done <- l.Acquire(context.Background()) }()
+	l.Release(0, nil)
+	require.NoError(t, <-done)
+	assert.Equal(t, Status{Limit: 1, InUse: 1}, l.Status())
+}
+
+func TestLimiterAcquireN(t *testing.T) {
+	l := NewLimiter("test", Config{RequestsPerSecond: 20, Burst: 2, Adaptive: AdaptiveConfig{Enabled: true, MinConcurrency: 1, MaxConcurrency: 1, Window: 100}})
+
+	// A batch call larger than the burst takes a token for each request, in chunks of the burst, and a single slot
+	start := time.Now()
+	require.NoError(t, l.AcquireN(context.Background(), 6))
+	assert.GreaterOrEqual(t, time.Since(start), 200*time.Millisecond)
+	assert.Equal(t, 1, l.Status().InUse)
+	l.Release(0, nil)
+	assert.Equal(t, 0, l.Status().InUse)
+
+	// The slot is released if the context is done waiting for the tokens
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	assert.Error(t, l.AcquireN(ctx, 10))
+	assert.Equal(t, 0, l.Status().InUse)
+}
+
+func TestLimiterTryAcquireToken(t *testing.T) {
+	assert.True(t, NewLimiter("test", Config{}).TryAcquireToken())
+
+	// The tokens are only taken if they are available right away
+	l := NewLimiter("test", Config{RequestsPerSecond: 1, Burst: 2})
+	require.NoError(t, l.Acquire(context.Background()))
+	assert.True(t, l.TryAcquireToken())
+	assert.False(t, l.TryAcquireToken())
+}
@@ -0,0 +1,108 @@
+package monitor
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/0xPolygonHermez/zkevm-pool-manager/log"
+	"github.com/0xPolygonHermez/zkevm-pool-manager/rpcclient"
+	"github.com/ethereum/go-ethereum"
+)
+
+// runBatchTracker periodically updates the status of the confirmed txs to virtualized and verified, and the
+// virtualization and verification times of the failed txs, following the state of their L2 batches in the L2 node.
+// Only the leader instance updates the txs. It's run by the batch tracker supervisor, that restarts it if it returns an
+// error
+func (m *Monitor) runBatchTracker(ctx context.Context, workerNum int, ready func()) error {
+	dialCtx, cancel := context.WithTimeout(ctx, m.cfg.RPCReadTimeout.Duration)
+	defer cancel()
+
+	rpcClient, err := m.dialL2Node(dialCtx, m.cfg.L2NodeURL, m.cfg.L2NodeAuth)
+	if err != nil {
+		return fmt.Errorf("error creating rpc client for %s, err: %v", m.cfg.L2NodeURL, err)
+	}
+	defer rpcClient.Close()
+
+	// Check the L2 node supports the zkevm namespace before start tracking the batches
+	_, err = rpcClient.VirtualBatchNumber(dialCtx)
+	if err != nil {
+		return fmt.Errorf("error getting virtual batch number from L2 node %s, err: %v", m.cfg.L2NodeURL, err)
+	}
+
+	log.Debugf("batch-tracker: started")
+	ready()
+
+	for {
+		select {
+		case <-ctx.Done():
+			log.Debugf("batch-tracker: stopped")
+			return nil
+		case <-time.After(m.cfg.BatchTracking.CheckInterval.Duration):
+		}
+
+		if !m.leader.IsLeader() {
+			continue
+		}
+
+		if err := m.updateL2TransactionsBatchStatus(ctx, rpcClient); err != nil && ctx.Err() == nil {
+			return err
+		}
+	}
+}
+
+// updateL2TransactionsBatchStatus sets the batch number of the confirmed and failed txs and updates them with the last
+// virtualized and verified batches. It returns an error if the calls to the L2 node fail, the errors updating the pool
+// database are logged and retried in the next check
+func (m *Monitor) updateL2TransactionsBatchStatus(ctx context.Context, rpcClient rpcclient.L2NodeClient) error {
+	blocks, err := m.poolDB.GetL2BlocksWithoutBatchNumber(ctx, uint64(m.cfg.BatchTracking.MaxBlocksPerCheck))
+	if err != nil {
+		log.Errorf("error getting L2 blocks without batch number from the pool db, error: %v", err)
+		return nil
+	}
+
+	for _, block := range blocks {
+		var batchNumber uint64
+		err := m.callL2Node(ctx, func(ctx context.Context) (err error) {
+			batchNumber, err = rpcClient.BatchNumberByBlockNumber(ctx, block)
+			return err
+		})
+		if errors.Is(err, ethereum.NotFound) {
+			log.Debugf("batch-tracker: batch of block %d not found", block)
+			continue
+		} else if err != nil {
+			return fmt.Errorf("error getting batch number of block %d from L2 node %s, err: %v", block, m.cfg.L2NodeURL, err)
+		}
+
+		err = m.poolDB.UpdateL2BlockBatchNumber(ctx, block, batchNumber)
+		if err != nil {
+			log.Errorf("error updating batch number of block %d in the pool db, error: %v", block, err)
+			return nil
+		}
+	}
+
+	var virtualBatch, verifiedBatch uint64
+	err = m.callL2Node(ctx, func(ctx context.Context) (err error) {
+		if virtualBatch, err = rpcClient.VirtualBatchNumber(ctx); err != nil {
+			return err
+		}
+		verifiedBatch, err = rpcClient.VerifiedBatchNumber(ctx)
+		return err
+	})
+	if err != nil {
+		return fmt.Errorf("error getting virtual and verified batch numbers from L2 node %s, err: %v", m.cfg.L2NodeURL, err)
+	}
+
+	virtualized, verified, err := m.poolDB.UpdateL2TransactionsBatchStatus(ctx, virtualBatch, verifiedBatch)
+	if err != nil {
+		log.Errorf("error updating virtualized and verified txs in the pool db, error: %v", err)
+		return nil
+	}
+	log.Debugf("batch-tracker: virtual batch: %d, verified batch: %d", virtualBatch, verifiedBatch)
+	if virtualized > 0 || verified > 0 {
+		log.Infof("%d txs have been virtualized and %d txs have been verified", virtualized, verified)
+	}
+
+	return nil
+}
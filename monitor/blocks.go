@@ -0,0 +1,152 @@
+package monitor
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/0xPolygonHermez/zkevm-pool-manager/log"
+	"github.com/0xPolygonHermez/zkevm-pool-manager/rpcclient"
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	ethTypes "github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+// newHeadsBufferSize is the size of the buffer of the headers received from the newHeads subscription
+const newHeadsBufferSize = 16
+
+// runBlockTracker follows the new L2 blocks and enqueues the monitor requests of the txs included in them, so their
+// receipts are requested once they are available. The new blocks are notified by the newHeads subscription, or polled
+// every BlockTracking.PollInterval if the L2 node transport doesn't support subscriptions. It's run by the block tracker
+// supervisor, that restarts it if it returns an error
+func (m *Monitor) runBlockTracker(ctx context.Context, workerNum int, ready func()) error {
+	dialCtx, cancel := context.WithTimeout(ctx, m.cfg.RPCReadTimeout.Duration)
+	defer cancel()
+
+	rpcClient, err := m.dialL2Node(dialCtx, m.cfg.L2NodeURL, m.cfg.L2NodeAuth)
+	if err != nil {
+		return fmt.Errorf("error creating rpc client for %s, err: %v", m.cfg.L2NodeURL, err)
+	}
+	defer rpcClient.Close()
+
+	blockNumber, err := rpcClient.BlockNumber(dialCtx)
+	if err != nil {
+		return fmt.Errorf("error getting block number from L2 node %s, err: %v", m.cfg.L2NodeURL, err)
+	}
+	// The first time the blocks are followed from the current block, the txs included in previous blocks are detected
+	// polling their receipts. If the block tracker is restarted it continues from the last processed block
+	if m.lastBlock == 0 {
+		m.lastBlock = blockNumber
+	}
+
+	heads := make(chan *ethTypes.Header, newHeadsBufferSize)
+	sub, err := rpcClient.SubscribeNewHeads(ctx, heads)
+	if errors.Is(err, rpc.ErrNotificationsUnsupported) {
+		log.Infof("block-tracker: newHeads subscription not supported by L2 node, polling new blocks every %v", m.cfg.BlockTracking.PollInterval)
+	} else if err != nil {
+		return fmt.Errorf("error subscribing to new heads of L2 node %s, err: %v", m.cfg.L2NodeURL, err)
+	} else {
+		defer sub.Unsubscribe()
+	}
+
+	log.Debugf("block-tracker: started from block %d", m.lastBlock)
+	ready()
+
+	for {
+		if err := m.processNewBlocks(ctx, rpcClient, blockNumber); err != nil {
+			return err
+		}
+
+		var subErr <-chan error
+		var poll <-chan time.Time
+		if sub != nil {
+			subErr = sub.Err()
+		} else {
+			poll = time.After(m.cfg.BlockTracking.PollInterval.Duration)
+		}
+
+		select {
+		case <-ctx.Done():
+			log.Debugf("block-tracker: stopped")
+			return nil
+		case err := <-subErr:
+			return fmt.Errorf("error in newHeads subscription of L2 node %s, err: %v", m.cfg.L2NodeURL, err)
+		case head := <-heads:
+			blockNumber = head.Number.Uint64()
+		case <-poll:
+			err := m.callL2Node(ctx, func(ctx context.Context) (err error) {
+				blockNumber, err = rpcClient.BlockNumber(ctx)
+				return err
+			})
+			if err != nil {
+				return fmt.Errorf("error getting block number from L2 node %s, err: %v", m.cfg.L2NodeURL, err)
+			}
+		}
+	}
+}
+
+// processNewBlocks matches the txs of the blocks after the last processed block up to toBlock with the monitored txs
+func (m *Monitor) processNewBlocks(ctx context.Context, rpcClient rpcclient.L2NodeClient, toBlock uint64) error {
+	for number := m.lastBlock + 1; number <= toBlock && ctx.Err() == nil; number++ {
+		var hashes []common.Hash
+		err := m.callL2Node(ctx, func(ctx context.Context) (err error) {
+			hashes, err = rpcClient.BlockTransactionHashes(ctx, number)
+			return err
+		})
+		if errors.Is(err, ethereum.NotFound) {
+			// The block is not available yet in the L2 node, it will be processed with the next block
+			log.Debugf("block-tracker: block %d not found, waiting for next block", number)
+			return nil
+		} else if err != nil && ctx.Err() == nil {
+			return fmt.Errorf("error getting txs of block %d from L2 node %s, err: %v", number, m.cfg.L2NodeURL, err)
+		} else if err != nil {
+			return nil
+		}
+
+		matched := m.matchBlockTransactions(number, hashes)
+		log.Debugf("block-tracker: block %d processed, txs: %d, monitored txs: %d", number, len(hashes), matched)
+		m.lastBlock = number
+	}
+
+	return nil
+}
+
+// matchBlockTransactions enqueues the monitor requests of the monitored txs included in the block. Returns the number of
+// matched txs
+func (m *Monitor) matchBlockTransactions(number uint64, hashes []common.Hash) int {
+	matched := 0
+	for _, hash := range hashes {
+		request, found := m.monitoredRequest(hash)
+		if !found {
+			continue
+		}
+
+		// The request is only enqueued if it's waiting in the retry scheduler, otherwise it's already being processed
+		if m.retryScheduler.delete(request) {
+			log.Debugf("tx %s found in block %d, requesting receipt", request.l2Tx.Tag(), number)
+			request.includedInBlock = true
+			m.enqueueMonitorRequest(request)
+			matched++
+		}
+	}
+
+	return matched
+}
+
+// callL2Node calls the L2 node with the RPCReadTimeout, within the limits of the calls to the L2 node
+func (m *Monitor) callL2Node(ctx context.Context, call func(ctx context.Context) error) error {
+	if err := m.limiter.Acquire(ctx); err != nil {
+		return err
+	}
+
+	callCtx, cancel := context.WithTimeout(ctx, m.cfg.RPCReadTimeout.Duration)
+	defer cancel()
+
+	start := time.Now()
+	err := call(callCtx)
+	m.limiter.Release(time.Since(start), err)
+
+	return err
+}
@@ -1,12 +1,24 @@
 package monitor
 
-import "github.com/0xPolygonHermez/zkevm-pool-manager/config/types"
+import (
+	"github.com/0xPolygonHermez/zkevm-pool-manager/config/types"
+	"github.com/0xPolygonHermez/zkevm-pool-manager/limiter"
+	"github.com/0xPolygonHermez/zkevm-pool-manager/rpcclient"
+	"github.com/0xPolygonHermez/zkevm-pool-manager/supervisor"
+)
 
 // Config for pool-manager monitor
 type Config struct {
-	// L2NodeURL defines the URL L2 node (RPC) used by the monitor to query for txs receipts
+	// L2NodeURL defines the URL L2 node (RPC) used by the monitor to query for txs receipts. The transport is selected by the
+	// URL scheme: http(s):// for HTTP, ws(s):// for WebSocket or the path of the socket for IPC
 	L2NodeURL string `mapstructure:"L2NodeURL"`
 
+	// L2NodeAuth is the configuration of the headers and authentication used to connect to the L2 node
+	L2NodeAuth rpcclient.Config `mapstructure:"L2NodeAuth"`
+
+	// Supervisor is the configuration of the supervision of the monitor workers
+	Supervisor supervisor.Config `mapstructure:"Supervisor"`
+
 	// Workers is the number of monitor workers to query for txs receipts
 	Workers uint16 `mapstructure:"Workers"`
 
@@ -19,9 +31,187 @@ type Config struct {
 	// RetryWaitInterval is the time the monitor worker will wait before to retry to get the tx receipt if it still doesn't exists
 	RetryWaitInterval types.Duration `mapstructure:"RetryWaitInterval"`
 
-	// TxLifetimeMax is the time a tx can be monitored waiting for the receipt
+	// TxLifetimeMax is the time a tx can be monitored waiting for the receipt. The clients can set a shorter lifetime
+	// for their txs when they send them
 	TxLifeTimeMax types.Duration `mapstructure:"TxLifeTimeMax"`
 
+	// Expiry is the configuration of the handling of the txs that reach their lifetime waiting for the receipt
+	Expiry ExpiryConfig `mapstructure:"Expiry"`
+
+	// RetrySchedule is the configuration of the persistence of the schedule of the receipt checks
+	RetrySchedule RetryScheduleConfig `mapstructure:"RetrySchedule"`
+
+	// SentTxsCheckInterval is the time the monitor waits between checks for sent txs in the pool database that are not
+	// monitored, like the txs of other pool-manager instances that are not running anymore. If leases are disabled only
+	// the leader instance checks them. It must be greater than 0 if leases are enabled (0 = disabled)
+	SentTxsCheckInterval types.Duration `mapstructure:"SentTxsCheckInterval"`
+
+	// ExpiredTxsCheckInterval is the time the leader instance waits between checks for sent txs in the pool database
+	// that have reached the TxLifeTimeMax, including the ones monitored by other instances. It must be greater than 0 if
+	// Leader is enabled (0 = disabled)
+	ExpiredTxsCheckInterval types.Duration `mapstructure:"ExpiredTxsCheckInterval"`
+
 	// RPCReadTimeout is the timeout for the RPC client to read the response from the L2 node
 	RPCReadTimeout types.Duration `mapstructure:"RPCReadTimeout"`
+
+	// RateLimit is the configuration of the limits of the calls to the L2 node
+	RateLimit limiter.Config `mapstructure:"RateLimit"`
+
+	// BlockTracking is the configuration of the detection of the receipts following the new L2 blocks
+	BlockTracking BlockTrackingConfig `mapstructure:"BlockTracking"`
+
+	// BatchReceipts is the configuration to get the txs receipts from the L2 node using JSON-RPC batch requests
+	BatchReceipts BatchReceiptsConfig `mapstructure:"BatchReceipts"`
+
+	// BatchTracking is the configuration of the tracking of the virtualization and verification of the L2 batches of
+	// the confirmed and failed txs
+	BatchTracking BatchTrackingConfig `mapstructure:"BatchTracking"`
+
+	// ReorgDetection is the configuration of the detection of the L2 reorgs that remove the blocks of the confirmed txs
+	ReorgDetection ReorgDetectionConfig `mapstructure:"ReorgDetection"`
+
+	// DataStream is the configuration of the detection of the receipts reading the L2 blocks from the data stream of
+	// the sequencer. It's an alternative to BlockTracking
+	DataStream DataStreamConfig `mapstructure:"DataStream"`
+
+	// DropDetection is the configuration of the detection of the sent txs dropped by the sequencer
+	DropDetection DropDetectionConfig `mapstructure:"DropDetection"`
+}
+
+// RetryScheduleConfig for persisting the time of the next check of the receipt of each monitored tx, and the number of
+// checks done, in the pool database. When the sent txs are loaded from the pool database, after a restart or when they
+// are taken over from other instances, the receipts are checked at their persisted time. The overdue checks are spread
+// over the RestoreJitter, instead of checking all of them at once
+type RetryScheduleConfig struct {
+	// Enabled enables the persistence of the schedule of the receipt checks
+	Enabled bool `mapstructure:"Enabled"`
+
+	// FlushInterval is the time the monitor waits between writes of the updated schedules to the pool database
+	FlushInterval types.Duration `mapstructure:"FlushInterval"`
+
+	// RestoreJitter is the maximum random delay of the overdue checks of the txs loaded from the pool database
+	RestoreJitter types.Duration `mapstructure:"RestoreJitter"`
+}
+
+const (
+	// ExpiryPolicyExpire updates the txs that reach their lifetime to expired
+	ExpiryPolicyExpire = "expire"
+	// ExpiryPolicyResend resends the txs that reach their lifetime up to MaxResends times, then they are expired
+	ExpiryPolicyResend = "resend"
+	// ExpiryPolicyCheckNonce checks the nonce of the account of the txs that reach their lifetime. If it has moved past
+	// the tx nonce the tx is updated to replaced, otherwise it's expired
+	ExpiryPolicyCheckNonce = "checkNonce"
+
+	// LifetimeFromReceived counts the lifetime of the txs from the time they were received, or requeued from the
+	// dead-letter store
+	LifetimeFromReceived = "received"
+	// LifetimeFromLastSent counts the lifetime of the txs from the last time they were sent to the sequencer
+	LifetimeFromLastSent = "lastSent"
+)
+
+// ExpiryConfig for handling the txs that reach their lifetime waiting for the receipt. The lifetime of a tx is the
+// TxLifeTimeMax, or the shorter lifetime set by the client when the tx was sent
+type ExpiryConfig struct {
+	// Policy is the action taken when a tx reaches its lifetime: "expire", "resend" or "checkNonce"
+	Policy string `mapstructure:"Policy"`
+
+	// MaxResends is the number of times a tx is resent before it's expired, if the Policy is "resend"
+	MaxResends uint64 `mapstructure:"MaxResends"`
+
+	// LifetimeFrom is the time the lifetime of the txs is counted from: "received" or "lastSent". The "resend" policy
+	// requires "lastSent", so the resent txs get a new lifetime
+	LifetimeFrom string `mapstructure:"LifetimeFrom"`
+}
+
+// DropDetectionConfig for detecting the sent txs dropped by the sequencer, instead of waiting for them to expire. If the
+// receipt of a tx is still not available CheckAfter the tx was sent, the monitor checks if the tx is still known by the
+// L2 node (eth_getTransactionByHash). If not, the tx is marked as replaced if the nonce of the account has moved past the
+// tx nonce, as another tx with the same nonce has been included in a block, or it's resent otherwise
+type DropDetectionConfig struct {
+	// Enabled enables the detection of the dropped txs
+	Enabled bool `mapstructure:"Enabled"`
+
+	// CheckAfter is the time the monitor waits since the tx was sent, or since the last check, before checking if the
+	// tx has been dropped
+	CheckAfter types.Duration `mapstructure:"CheckAfter"`
+}
+
+// DataStreamConfig is the configuration of the detection of the receipts reading the L2 blocks from the data stream of
+// the sequencer. The monitor connects to the data stream server as a client, decodes the L2 block and tx entries and
+// requests the receipt of the monitored txs as soon as their block is streamed, to get their execution status, so the
+// streamed txs are still confirmed with a receipt RPC. The last L2 block read is persisted in the pool database, per
+// instance when leases are enabled, so after a restart the monitor resumes streaming from its bookmark
+type DataStreamConfig struct {
+	// Enabled enables the detection of the receipts reading the L2 blocks from the data stream
+	Enabled bool `mapstructure:"Enabled"`
+
+	// Server is the address (host:port) of the data stream server of the sequencer
+	Server string `mapstructure:"Server"`
+
+	// StragglerWaitInterval is the time the monitor waits before polling the receipt of a tx that has not been found
+	// in the streamed L2 blocks. It replaces the InitialWaitInterval and RetryWaitInterval for these txs
+	StragglerWaitInterval types.Duration `mapstructure:"StragglerWaitInterval"`
+}
+
+// ReorgDetectionConfig for detecting the L2 reorgs of the recently confirmed txs. The monitor checks the block hash of
+// the receipts of the confirmed and failed txs included in the last Depth blocks is still canonical, and if not the txs
+// are moved back to be monitored or resent. It only runs in the leader instance
+type ReorgDetectionConfig struct {
+	// Enabled enables the detection of the L2 reorgs
+	Enabled bool `mapstructure:"Enabled"`
+
+	// Depth is the number of L2 blocks from the last block whose confirmed txs are checked. The txs in older blocks are
+	// considered final
+	Depth uint64 `mapstructure:"Depth"`
+
+	// CheckInterval is the time the monitor waits between checks for L2 reorgs
+	CheckInterval types.Duration `mapstructure:"CheckInterval"`
+
+	// Resend defines if the reorged txs are moved to resend status, so they are sent again to the sequencer. Otherwise
+	// they are moved to sent status and monitored again, waiting for the sequencer to include them in a new block
+	Resend bool `mapstructure:"Resend"`
+}
+
+// BatchTrackingConfig for tracking the virtualization and verification on L1 of the L2 batches of the confirmed and
+// failed txs, using the zkevm namespace of the L2 node. The confirmed txs are updated to virtualized and then to
+// verified, when the tx is final. The failed txs keep their status and only their virtualization and verification times
+// are set. It only runs in the leader instance
+type BatchTrackingConfig struct {
+	// Enabled enables the tracking of the L2 batches of the confirmed and failed txs
+	Enabled bool `mapstructure:"Enabled"`
+
+	// CheckInterval is the time the monitor waits between checks for new virtualized and verified batches
+	CheckInterval types.Duration `mapstructure:"CheckInterval"`
+
+	// MaxBlocksPerCheck is the maximum number of L2 blocks whose batch number is requested to the L2 node in each check
+	MaxBlocksPerCheck uint16 `mapstructure:"MaxBlocksPerCheck"`
+}
+
+// BatchReceiptsConfig for getting the txs receipts from the L2 node using JSON-RPC batch requests
+type BatchReceiptsConfig struct {
+	// Enabled defines if the monitor workers coalesce the queued monitor requests into JSON-RPC batch requests
+	Enabled bool `mapstructure:"Enabled"`
+
+	// MaxSize is the maximum number of receipts requested in a single batch request
+	MaxSize uint16 `mapstructure:"MaxSize"`
+
+	// MaxLinger is the maximum time a worker waits for more monitor requests to fill the batch request before sending it
+	MaxLinger types.Duration `mapstructure:"MaxLinger"`
+}
+
+// BlockTrackingConfig is the configuration of the detection of the receipts following the new L2 blocks. Instead of
+// polling the receipt of each monitored tx, the monitor gets the tx hashes of each new L2 block once and only requests
+// the receipts of the monitored txs included in the block
+type BlockTrackingConfig struct {
+	// Enabled enables the detection of the receipts following the new L2 blocks
+	Enabled bool `mapstructure:"Enabled"`
+
+	// PollInterval is the time the monitor waits between checks for new L2 blocks. It's only used if the L2 node
+	// transport doesn't support the newHeads subscription (HTTP)
+	PollInterval types.Duration `mapstructure:"PollInterval"`
+
+	// StragglerWaitInterval is the time the monitor waits before polling the receipt of a tx that has not been found
+	// in the new L2 blocks, like the txs included in a block before the tx was monitored. It replaces the
+	// InitialWaitInterval and RetryWaitInterval for these txs
+	StragglerWaitInterval types.Duration `mapstructure:"StragglerWaitInterval"`
 }
@@ -0,0 +1,161 @@
+package monitor
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/0xPolygonHermez/zkevm-pool-manager/datastream"
+	"github.com/0xPolygonHermez/zkevm-pool-manager/log"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/jackc/pgx/v4"
+)
+
+// runDataStreamReader reads the L2 blocks from the data stream of the sequencer and enqueues the monitor requests of the
+// txs included in them, so their receipts are requested once they are available. It resumes streaming from the
+// bookmark of the last L2 block read, or from the end of the stream the first time. It's run by the data stream reader
+// supervisor, that restarts it if it returns an error
+func (m *Monitor) runDataStreamReader(ctx context.Context, workerNum int, ready func()) error {
+	client, err := m.startDataStream(ctx)
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+
+	ready()
+
+	// block is the L2 block being streamed, its txs are matched once the block is closed
+	var block *datastream.L2BlockStart
+	var hashes []common.Hash
+	for {
+		entry, err := client.NextEntry(ctx)
+		if ctx.Err() != nil {
+			log.Debugf("datastream: stopped")
+			return nil
+		} else if err != nil {
+			return fmt.Errorf("error reading from data stream %s, err: %v", m.cfg.DataStream.Server, err)
+		}
+
+		switch entry.Type {
+		case datastream.EntryTypeL2BlockStart:
+			block, err = datastream.DecodeL2BlockStart(entry.Data)
+			if err != nil {
+				return fmt.Errorf("error decoding data stream entry %d, err: %v", entry.Number, err)
+			}
+			hashes = hashes[:0]
+
+		case datastream.EntryTypeL2Tx:
+			if block == nil {
+				// The streaming started in the middle of a block
+				continue
+			}
+			tx, err := datastream.DecodeL2Transaction(entry.Data)
+			if err != nil {
+				return fmt.Errorf("error decoding data stream entry %d, err: %v", entry.Number, err)
+			}
+			if tx.IsValid == 0 {
+				continue
+			}
+			hash, err := tx.Hash()
+			if err != nil {
+				return fmt.Errorf("error decoding tx of data stream entry %d, err: %v", entry.Number, err)
+			}
+			hashes = append(hashes, hash)
+
+		case datastream.EntryTypeL2BlockEnd:
+			if block == nil {
+				continue
+			}
+			blockEnd, err := datastream.DecodeL2BlockEnd(entry.Data)
+			if err != nil {
+				return fmt.Errorf("error decoding data stream entry %d, err: %v", entry.Number, err)
+			}
+			if blockEnd.L2BlockNumber != block.L2BlockNumber {
+				return fmt.Errorf("unexpected end of block %d in data stream entry %d, expected end of block %d", blockEnd.L2BlockNumber, entry.Number, block.L2BlockNumber)
+			}
+			m.processStreamedBlock(ctx, block.L2BlockNumber, hashes)
+			block = nil
+		}
+	}
+}
+
+// startDataStream connects to the data stream server and starts streaming from the bookmark of the last L2 block read,
+// so the txs included in the blocks streamed while the monitor was stopped are detected. The last block is streamed
+// again, but its txs are not monitored anymore. If no block has been read yet, it starts from the end of the stream
+func (m *Monitor) startDataStream(ctx context.Context) (*datastream.Client, error) {
+	startCtx, cancel := context.WithTimeout(ctx, m.cfg.RPCReadTimeout.Duration)
+	defer cancel()
+
+	server := m.cfg.DataStream.Server
+	client, err := datastream.Dial(startCtx, server, datastream.StreamTypeSequencer)
+	if err != nil {
+		return nil, fmt.Errorf("error connecting to data stream %s, err: %v", server, err)
+	}
+
+	fromBlock := m.lastStreamedBlock
+	if fromBlock == 0 {
+		fromBlock, err = m.poolDB.GetDataStreamCheckpoint(startCtx, m.dataStreamCheckpointKey())
+		if err != nil && !errors.Is(err, pgx.ErrNoRows) {
+			client.Close()
+			return nil, fmt.Errorf("error getting the last L2 block read from data stream %s, err: %v", server, err)
+		}
+	}
+
+	if fromBlock > 0 {
+		err = client.StartFromBookmark(startCtx, datastream.L2BlockBookmark(fromBlock))
+		if err == nil {
+			log.Infof("datastream: resumed from block %d", fromBlock)
+			return client, nil
+		}
+
+		client.Close()
+		if !errors.Is(err, datastream.ErrBookmarkNotFound) {
+			return nil, fmt.Errorf("error starting data stream %s from block %d, err: %v", server, fromBlock, err)
+		}
+
+		// The server closes the connection after a failed command
+		log.Warnf("datastream: block %d not found in data stream %s, starting from the end of the stream", fromBlock, server)
+		client, err = datastream.Dial(startCtx, server, datastream.StreamTypeSequencer)
+		if err != nil {
+			return nil, fmt.Errorf("error connecting to data stream %s, err: %v", server, err)
+		}
+	}
+
+	entries, err := client.TotalEntries(startCtx)
+	if err == nil {
+		err = client.StartFromEntry(startCtx, entries)
+	}
+	if err != nil {
+		client.Close()
+		return nil, fmt.Errorf("error starting data stream %s, err: %v", server, err)
+	}
+
+	log.Infof("datastream: started from entry %d", entries)
+	return client, nil
+}
+
+// processStreamedBlock matches the txs of the streamed L2 block with the monitored txs and persists the block as the
+// last block read from the data stream. Without leases the instances share the checkpoint, so only the leader
+// persists it
+func (m *Monitor) processStreamedBlock(ctx context.Context, number uint64, hashes []common.Hash) {
+	matched := m.matchBlockTransactions(number, hashes)
+	log.Debugf("datastream: block %d processed, txs: %d, monitored txs: %d", number, len(hashes), matched)
+
+	m.lastStreamedBlock = number
+	if m.poolDB.OwnerID() == "" && !m.leader.IsLeader() {
+		return
+	}
+	if err := m.poolDB.UpdateDataStreamCheckpoint(ctx, m.dataStreamCheckpointKey(), number); err != nil && ctx.Err() == nil {
+		log.Errorf("error updating the last L2 block read from data stream to %d, error: %v", number, err)
+	}
+}
+
+// dataStreamCheckpointKey returns the key of the last L2 block read from the data stream in the pool database. With
+// leases each instance monitors its own txs, so each one keeps its own checkpoint, and an instance that is behind
+// doesn't move back the checkpoint of the others
+func (m *Monitor) dataStreamCheckpointKey() string {
+	if ownerID := m.poolDB.OwnerID(); ownerID != "" {
+		return m.cfg.DataStream.Server + "/" + ownerID
+	}
+	return m.cfg.DataStream.Server
+}
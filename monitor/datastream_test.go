@@ -0,0 +1,156 @@
+//go:build !race
+
+// The data stream server has data races in the broadcasting of the committed entries, so this test is not run with the
+// race detector
+
+package monitor
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/0xPolygonHermez/zkevm-data-streamer/datastreamer"
+	dsLog "github.com/0xPolygonHermez/zkevm-data-streamer/log"
+	"github.com/0xPolygonHermez/zkevm-pool-manager/config/types"
+	"github.com/0xPolygonHermez/zkevm-pool-manager/datastream"
+	"github.com/0xPolygonHermez/zkevm-pool-manager/rpcclient"
+	"github.com/0xPolygonHermez/zkevm-pool-manager/supervisor"
+	poolTypes "github.com/0xPolygonHermez/zkevm-pool-manager/types"
+	ethTypes "github.com/ethereum/go-ethereum/core/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newTestStreamServer starts an in-process data stream server and returns its address and a function to add L2 blocks
+// with the txs to the stream
+func newTestStreamServer(t *testing.T) (string, func(number uint64, txs ...*ethTypes.Transaction)) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	port := listener.Addr().(*net.TCPAddr).Port
+	require.NoError(t, listener.Close())
+
+	logCfg := &dsLog.Config{Environment: "development", Level: "error", Outputs: []string{"stderr"}}
+	streamServer, err := datastreamer.NewServer(uint16(port), 1, 1001, datastream.StreamTypeSequencer, filepath.Join(t.TempDir(), "datastream.bin"), logCfg)
+	require.NoError(t, err)
+	require.NoError(t, streamServer.Start())
+
+	addBlock := func(number uint64, txs ...*ethTypes.Transaction) {
+		require.NoError(t, streamServer.StartAtomicOp())
+		_, err := streamServer.AddStreamBookmark(datastream.L2BlockBookmark(number))
+		require.NoError(t, err)
+		_, err = streamServer.AddStreamEntry(datastream.EntryTypeL2BlockStart, (&datastream.L2BlockStart{L2BlockNumber: number}).Encode())
+		require.NoError(t, err)
+		for _, tx := range txs {
+			encoded, err := tx.MarshalBinary()
+			require.NoError(t, err)
+			_, err = streamServer.AddStreamEntry(datastream.EntryTypeL2Tx, (&datastream.L2Transaction{IsValid: 1, Encoded: encoded}).Encode())
+			require.NoError(t, err)
+		}
+		_, err = streamServer.AddStreamEntry(datastream.EntryTypeL2BlockEnd, (&datastream.L2BlockEnd{L2BlockNumber: number}).Encode())
+		require.NoError(t, err)
+		require.NoError(t, streamServer.CommitAtomicOp())
+	}
+
+	return fmt.Sprintf("127.0.0.1:%d", port), addBlock
+}
+
+// startTestDataStreamMonitor starts a monitor reading the L2 blocks from the data stream server. The monitor is stopped
+// by the returned function
+func startTestDataStreamMonitor(t *testing.T, server string, poolDB *fakePoolDB, l2NodeClient rpcclient.L2NodeClient, l2Txs ...*poolTypes.L2Transaction) (*Monitor, func()) {
+	m := NewMonitor(Config{
+		Workers:           1,
+		QueueSize:         10,
+		RPCReadTimeout:    types.NewDuration(time.Second),
+		RetryWaitInterval: types.NewDuration(time.Minute),
+		TxLifeTimeMax:     types.NewDuration(time.Hour),
+		Supervisor: supervisor.Config{
+			RestartInitialBackoff: types.NewDuration(10 * time.Millisecond),
+			RestartMaxBackoff:     types.NewDuration(10 * time.Millisecond),
+			StartupTimeout:        types.NewDuration(500 * time.Millisecond),
+		},
+		DataStream: DataStreamConfig{
+			Enabled:               true,
+			Server:                server,
+			StragglerWaitInterval: types.NewDuration(time.Minute),
+		},
+	}, poolDB, &fakeLeader{})
+	m.dialL2Node = func(ctx context.Context, url string, cfg rpcclient.Config) (rpcclient.L2NodeClient, error) {
+		return l2NodeClient, nil
+	}
+	for _, l2Tx := range l2Txs {
+		m.AddL2Transaction(l2Tx)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	m.Start(ctx)
+	require.NoError(t, m.WaitWorkersAlive())
+	require.NoError(t, m.dataStreamReader.WaitAlive())
+
+	return m, func() {
+		cancel()
+		stopCtx, stopCancel := context.WithTimeout(context.Background(), time.Second)
+		defer stopCancel()
+		require.NoError(t, m.Stop(stopCtx))
+	}
+}
+
+func TestDataStream(t *testing.T) {
+	server, addBlock := newTestStreamServer(t)
+
+	tx1 := ethTypes.NewTx(&ethTypes.LegacyTx{Nonce: 1})
+	tx2 := ethTypes.NewTx(&ethTypes.LegacyTx{Nonce: 2})
+	l2NodeClient := rpcclient.NewFakeL2NodeClient(1001)
+	l2NodeClient.SetReceipt(tx1.Hash(), &ethTypes.Receipt{Status: ethTypes.ReceiptStatusSuccessful})
+	l2NodeClient.SetReceipt(tx2.Hash(), &ethTypes.Receipt{Status: ethTypes.ReceiptStatusSuccessful})
+
+	// The monitor was stopped after reading block 1, and tx1 was included in block 2 in the meantime
+	poolDB := &fakePoolDB{statuses: make(map[uint64]string), checkpoints: map[string]uint64{server: 1}}
+	addBlock(1)
+	addBlock(2, tx1)
+
+	m, stop := startTestDataStreamMonitor(t, server, poolDB, l2NodeClient,
+		&poolTypes.L2Transaction{Id: 1, Hash: tx1.Hash().String(), ReceivedAt: time.Now()},
+		&poolTypes.L2Transaction{Id: 2, Hash: tx2.Hash().String(), ReceivedAt: time.Now()})
+	defer stop()
+
+	// The streaming is resumed from the bookmark of block 1, so tx1 is detected in block 2
+	require.Eventually(t, func() bool { return poolDB.status(1) == poolTypes.TxStatusConfirmed }, time.Second, 10*time.Millisecond)
+	require.Eventually(t, func() bool { return poolDB.checkpoint(server) == 2 }, time.Second, 10*time.Millisecond)
+	assert.Empty(t, poolDB.status(2))
+
+	// The new blocks are streamed as they are added
+	addBlock(3, tx2)
+	require.Eventually(t, func() bool { return poolDB.status(2) == poolTypes.TxStatusConfirmed }, time.Second, 10*time.Millisecond)
+	require.Eventually(t, func() bool { return poolDB.checkpoint(server) == 3 }, time.Second, 10*time.Millisecond)
+	assert.Equal(t, 2, l2NodeClient.ReceiptCalls())
+	assert.Equal(t, 0, m.retryScheduler.len())
+}
+
+func TestDataStreamResumeWithLeases(t *testing.T) {
+	server, addBlock := newTestStreamServer(t)
+
+	tx := ethTypes.NewTx(&ethTypes.LegacyTx{Nonce: 1})
+	l2NodeClient := rpcclient.NewFakeL2NodeClient(1001)
+	l2NodeClient.SetReceipt(tx.Hash(), &ethTypes.Receipt{Status: ethTypes.ReceiptStatusSuccessful})
+
+	// The instance keeps its own checkpoint, the shared one is not written
+	poolDB := &fakePoolDB{statuses: make(map[uint64]string), checkpoints: make(map[string]uint64), ownerID: "instance-a"}
+	addBlock(1)
+	_, stop := startTestDataStreamMonitor(t, server, poolDB, l2NodeClient)
+	addBlock(2)
+	require.Eventually(t, func() bool { return poolDB.checkpoint(server+"/instance-a") == 2 }, time.Second, 10*time.Millisecond)
+	assert.Zero(t, poolDB.checkpoint(server))
+	stop()
+
+	// The instance restarted with the same owner id resumes from its checkpoint, so the tx included in a block while it
+	// was stopped is detected
+	addBlock(3, tx)
+	_, stop = startTestDataStreamMonitor(t, server, poolDB, l2NodeClient, &poolTypes.L2Transaction{Id: 1, Hash: tx.Hash().String(), ReceivedAt: time.Now()})
+	defer stop()
+	require.Eventually(t, func() bool { return poolDB.status(1) == poolTypes.TxStatusConfirmed }, time.Second, 10*time.Millisecond)
+	require.Eventually(t, func() bool { return poolDB.checkpoint(server+"/instance-a") == 3 }, time.Second, 10*time.Millisecond)
+}
@@ -0,0 +1,97 @@
+package monitor
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/0xPolygonHermez/zkevm-pool-manager/log"
+	"github.com/0xPolygonHermez/zkevm-pool-manager/rpcclient"
+	"github.com/0xPolygonHermez/zkevm-pool-manager/types"
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// droppedTxError is the error recorded in the error history of the dropped txs that are resent
+const droppedTxError = "tx dropped by the sequencer, it's not known by the L2 node"
+
+// isDropCheckDue returns true if DropDetection is enabled and the tx has been waiting for the receipt CheckAfter since
+// it was sent, or since the last check
+func (m *Monitor) isDropCheckDue(request *monitorRequest) bool {
+	if !m.cfg.DropDetection.Enabled {
+		return false
+	}
+
+	since := request.l2Tx.LastSentAt
+	if since.IsZero() {
+		since = request.l2Tx.QueuedAt()
+	}
+	if request.dropCheckedAt.After(since) {
+		since = request.dropCheckedAt
+	}
+
+	return time.Since(since) >= m.cfg.DropDetection.CheckAfter.Duration
+}
+
+// checkDroppedL2Transaction checks if the tx is still known by the L2 node. If not, the tx is marked as replaced if the
+// nonce of the account has moved past the tx nonce, or it's moved to resend status otherwise, so the sender sends it
+// again. Returns true if the tx has been dropped, then it's not monitored anymore
+func (m *Monitor) checkDroppedL2Transaction(request *monitorRequest, rpcClient rpcclient.L2NodeClient, workerNum int) bool {
+	request.dropCheckedAt = time.Now()
+	ctx := context.Background()
+
+	hash := common.HexToHash(request.l2Tx.Hash)
+	err := m.callL2Node(ctx, func(ctx context.Context) error {
+		_, err := rpcClient.TransactionByHash(ctx, hash)
+		return err
+	})
+	if err == nil {
+		log.Debugf("monitor-worker[%03d]: tx %s is still known by the L2 node", workerNum, request.l2Tx.Tag())
+		return false
+	} else if !errors.Is(err, ethereum.NotFound) {
+		log.Errorf("monitor-worker[%03d]: error checking if tx %s is known by the L2 node, error: %v", workerNum, request.l2Tx.Tag(), err)
+		return false
+	}
+
+	var nonce uint64
+	err = m.callL2Node(ctx, func(ctx context.Context) (err error) {
+		nonce, err = rpcClient.NonceAt(ctx, common.HexToAddress(request.l2Tx.FromAddress))
+		return err
+	})
+	if err != nil {
+		log.Errorf("monitor-worker[%03d]: error getting nonce of account %s of tx %s, error: %v", workerNum, request.l2Tx.FromAddress, request.l2Tx.Tag(), err)
+		return false
+	}
+
+	if nonce > request.l2Tx.Nonce {
+		// The tx may have been included in a block after it was checked, then its receipt is requested again
+		err := m.callL2Node(ctx, func(ctx context.Context) error {
+			_, err := rpcClient.TransactionReceipt(ctx, hash)
+			return err
+		})
+		if !errors.Is(err, ethereum.NotFound) {
+			return false
+		}
+
+		replacedBy, err := m.poolDB.UpdateL2TransactionReplaced(ctx, request.l2Tx.Id)
+		if err != nil {
+			log.Errorf("monitor-worker[%03d]: error updating tx %s status (%s) in the pool db, error: %v", workerNum, request.l2Tx.Tag(), types.TxStatusReplaced, err)
+			return false
+		}
+		if replacedBy == "" {
+			replacedBy = "unknown"
+		}
+		log.Infof("monitor-worker[%03d]: tx %s has been replaced by tx %s, account nonce: %d", workerNum, request.l2Tx.Tag(), replacedBy, nonce)
+	} else {
+		err := m.poolDB.UpdateL2TransactionStatus(ctx, request.l2Tx.Id, types.TxStatusResend, droppedTxError)
+		if err != nil {
+			log.Errorf("monitor-worker[%03d]: error updating tx %s status (%s) in the pool db, error: %v", workerNum, request.l2Tx.Tag(), types.TxStatusResend, err)
+			return false
+		}
+		log.Warnf("monitor-worker[%03d]: tx %s has been dropped by the sequencer, resending it", workerNum, request.l2Tx.Tag())
+	}
+
+	m.retryScheduler.delete(request)
+	m.untrackL2Transaction(request)
+	return true
+}
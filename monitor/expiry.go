@@ -0,0 +1,127 @@
+package monitor
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/0xPolygonHermez/zkevm-pool-manager/log"
+	"github.com/0xPolygonHermez/zkevm-pool-manager/rpcclient"
+	"github.com/0xPolygonHermez/zkevm-pool-manager/types"
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	ethTypes "github.com/ethereum/go-ethereum/core/types"
+)
+
+// expiresAt returns the time the tx reaches its lifetime waiting for the receipt. The lifetime is the one set by the
+// client, up to the TxLifeTimeMax, and it's counted from the time the tx was received, or requeued, or last sent
+func (m *Monitor) expiresAt(l2Tx *types.L2Transaction) time.Time {
+	lifetime := m.cfg.TxLifeTimeMax.Duration
+	if l2Tx.Lifetime > 0 && l2Tx.Lifetime < lifetime {
+		lifetime = l2Tx.Lifetime
+	}
+
+	since := l2Tx.QueuedAt()
+	if m.cfg.Expiry.LifetimeFrom == LifetimeFromLastSent && !l2Tx.LastSentAt.IsZero() {
+		since = l2Tx.LastSentAt
+	}
+
+	return since.Add(lifetime)
+}
+
+// expireMonitorRequest applies the expiry policy to the tx of the request that has reached its lifetime. The request
+// must have been taken from the retry scheduler
+func (m *Monitor) expireMonitorRequest(request *monitorRequest) {
+	switch m.cfg.Expiry.Policy {
+	case ExpiryPolicyCheckNonce:
+		// The nonce of the account is checked by a worker, after getting the receipt of the tx a last time
+		log.Debugf("monitor tx %s has expired, checking the nonce of the account", request.l2Tx.Tag())
+		request.expired = true
+		m.enqueueMonitorRequest(request)
+		return
+	case ExpiryPolicyResend:
+		if m.leader.IsLeader() && request.l2Tx.ExpiryResends < m.cfg.Expiry.MaxResends {
+			resent, err := m.poolDB.ResendExpiredL2Transaction(context.Background(), request.l2Tx.Id)
+			if err != nil {
+				log.Errorf("error updating tx %s status (%s) in the pool db, error: %v", request.l2Tx.Tag(), types.TxStatusResend, err)
+			} else if resent {
+				log.Infof("monitor tx %s has expired, resending it (%d/%d)", request.l2Tx.Tag(), request.l2Tx.ExpiryResends+1, m.cfg.Expiry.MaxResends)
+			}
+			m.untrackL2Transaction(request)
+			return
+		}
+	}
+
+	// Only the leader updates the status of the expired txs, followers just stop monitoring them
+	if m.leader.IsLeader() {
+		log.Debugf("monitor tx %s has expired, updating status", request.l2Tx.Tag())
+		err := m.poolDB.UpdateL2TransactionStatus(context.Background(), request.l2Tx.Id, types.TxStatusExpired, "")
+		if err != nil {
+			log.Errorf("error updating tx %s status (%s) in the pool db, error: %v", request.l2Tx.Tag(), types.TxStatusExpired, err)
+		}
+	} else {
+		log.Debugf("monitor tx %s has expired, stop monitoring it", request.l2Tx.Tag())
+	}
+	m.untrackL2Transaction(request)
+}
+
+// checkExpiredL2TransactionNonce checks the nonce of the account of the expired tx whose receipt is not available. The
+// tx is updated to replaced if the nonce has moved past the tx nonce, as another tx with the same nonce has been
+// included in a block, or to expired otherwise. As the nonce also moves past the tx nonce when the tx itself is
+// included in a block, its receipt is requested again before it's replaced. Only the leader updates the status of the expired txs, followers just
+// stop monitoring them. Returns false if the nonce can't be checked, then the check is retried
+func (m *Monitor) checkExpiredL2TransactionNonce(request *monitorRequest, rpcClient rpcclient.L2NodeClient, workerNum int) bool {
+	if !m.leader.IsLeader() {
+		log.Debugf("monitor-worker[%03d]: tx %s has expired, stop monitoring it", workerNum, request.l2Tx.Tag())
+		m.retryScheduler.delete(request)
+		m.untrackL2Transaction(request)
+		return true
+	}
+
+	ctx := context.Background()
+	var nonce uint64
+	err := m.callL2Node(ctx, func(ctx context.Context) (err error) {
+		nonce, err = rpcClient.NonceAt(ctx, common.HexToAddress(request.l2Tx.FromAddress))
+		return err
+	})
+	if err != nil {
+		log.Errorf("monitor-worker[%03d]: error getting nonce of account %s of expired tx %s, error: %v", workerNum, request.l2Tx.FromAddress, request.l2Tx.Tag(), err)
+		return false
+	}
+
+	if nonce > request.l2Tx.Nonce {
+		var receipt *ethTypes.Receipt
+		err := m.callL2Node(ctx, func(ctx context.Context) (err error) {
+			receipt, err = rpcClient.TransactionReceipt(ctx, common.HexToHash(request.l2Tx.Hash))
+			return err
+		})
+		if err == nil {
+			m.processReceipt(request, receipt, nil, rpcClient, workerNum)
+			return true
+		} else if !errors.Is(err, ethereum.NotFound) {
+			log.Errorf("monitor-worker[%03d]: error getting receipt for expired tx %s, error: %v", workerNum, request.l2Tx.Tag(), err)
+			return false
+		}
+
+		replacedBy, err := m.poolDB.UpdateL2TransactionReplaced(ctx, request.l2Tx.Id)
+		if err != nil {
+			log.Errorf("monitor-worker[%03d]: error updating tx %s status (%s) in the pool db, error: %v", workerNum, request.l2Tx.Tag(), types.TxStatusReplaced, err)
+			return false
+		}
+		if replacedBy == "" {
+			replacedBy = "unknown"
+		}
+		log.Infof("monitor-worker[%03d]: expired tx %s has been replaced by tx %s, account nonce: %d", workerNum, request.l2Tx.Tag(), replacedBy, nonce)
+	} else {
+		err := m.poolDB.UpdateL2TransactionStatus(ctx, request.l2Tx.Id, types.TxStatusExpired, "")
+		if err != nil {
+			log.Errorf("monitor-worker[%03d]: error updating tx %s status (%s) in the pool db, error: %v", workerNum, request.l2Tx.Tag(), types.TxStatusExpired, err)
+			return false
+		}
+		log.Infof("monitor-worker[%03d]: tx %s has expired, account nonce: %d", workerNum, request.l2Tx.Tag(), nonce)
+	}
+
+	m.retryScheduler.delete(request)
+	m.untrackL2Transaction(request)
+	return true
+}
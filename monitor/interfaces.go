@@ -2,11 +2,30 @@ package monitor
 
 import (
 	"context"
+	"time"
 
 	"github.com/0xPolygonHermez/zkevm-pool-manager/types"
 )
 
 type poolDBInterface interface {
 	UpdateL2TransactionStatus(ctx context.Context, id uint64, newStatus string, errorMsg string) error
+	UpdateL2TransactionReceipt(ctx context.Context, id uint64, newStatus string, receipt *types.L2TransactionReceipt) error
 	GetL2TransactionsToMonitor(ctx context.Context) ([]*types.L2Transaction, error)
+	UpdateExpiredL2Transactions(ctx context.Context, maxLifetime time.Duration, fromLastSent bool, maxResends uint64, excludedIDs []uint64) (int64, int64, error)
+	ResendExpiredL2Transaction(ctx context.Context, id uint64) (bool, error)
+	GetL2BlocksWithoutBatchNumber(ctx context.Context, limit uint64) ([]uint64, error)
+	UpdateL2BlockBatchNumber(ctx context.Context, blockNumber uint64, batchNumber uint64) error
+	UpdateL2TransactionsBatchStatus(ctx context.Context, virtualBatch uint64, verifiedBatch uint64) (int64, int64, error)
+	GetL2BlocksToCheckReorg(ctx context.Context, fromBlock uint64) ([]*types.L2Block, error)
+	ReorgL2Transactions(ctx context.Context, block *types.L2Block, canonicalHash string, newStatus string) ([]*types.L2Transaction, error)
+	GetDataStreamCheckpoint(ctx context.Context, server string) (uint64, error)
+	UpdateDataStreamCheckpoint(ctx context.Context, server string, blockNumber uint64) error
+	UpdateL2TransactionReplaced(ctx context.Context, id uint64) (string, error)
+	UpdateL2TransactionsNextCheck(ctx context.Context, checks []types.L2TransactionCheck) (int64, error)
+	OwnerID() string
+}
+
+type leaderInterface interface {
+	IsLeader() bool
+	OnAcquired(fn func())
 }
@@ -3,49 +3,189 @@ package monitor
 import (
 	"context"
 	"errors"
+	"fmt"
+	"runtime/debug"
 	"sync"
 	"time"
 
+	"github.com/0xPolygonHermez/zkevm-pool-manager/limiter"
 	"github.com/0xPolygonHermez/zkevm-pool-manager/log"
+	"github.com/0xPolygonHermez/zkevm-pool-manager/rpcclient"
+	"github.com/0xPolygonHermez/zkevm-pool-manager/supervisor"
 	"github.com/0xPolygonHermez/zkevm-pool-manager/types"
 	"github.com/ethereum/go-ethereum"
 	"github.com/ethereum/go-ethereum/common"
-	"github.com/ethereum/go-ethereum/ethclient"
+	ethTypes "github.com/ethereum/go-ethereum/core/types"
 )
 
 type Monitor struct {
-	cfg              Config
-	poolDB           poolDBInterface
-	requestChan      chan *monitorRequest
-	requestRetryList *monitorRequestList
-	requestRetryCond *sync.Cond
+	cfg            Config
+	poolDB         poolDBInterface
+	leader         leaderInterface
+	workers        *supervisor.Supervisor
+	requestChan    chan *monitorRequest
+	retryScheduler *requestScheduler
+	// monitored holds the ids of the txs that are being monitored, to avoid monitoring the same tx twice
+	monitored map[uint64]struct{}
+	// monitoredHashes holds the requests of the monitored txs indexed by tx hash, to match them with the txs of the new L2 blocks
+	monitoredHashes map[common.Hash]*monitorRequest
+	monitoredMutex  sync.Mutex
+	// stopped is set when the monitor is stopping, then no new txs are monitored
+	stopped bool
+	// stopWorkers stops the monitor workers
+	stopWorkers context.CancelFunc
+	// limiter limits the calls to the L2 node
+	limiter *limiter.Limiter
+	// dialL2Node creates the clients used by the workers to get the receipts from the L2 node
+	dialL2Node func(ctx context.Context, url string, cfg rpcclient.Config) (rpcclient.L2NodeClient, error)
+	// blockTracker supervises the block tracker that follows the new L2 blocks, if BlockTracking is enabled
+	blockTracker *supervisor.Supervisor
+	// lastBlock is the last L2 block processed by the block tracker
+	lastBlock uint64
+	// batchTracker supervises the batch tracker that follows the virtualization and verification of the L2 batches, if
+	// BatchTracking is enabled
+	batchTracker *supervisor.Supervisor
+	// reorgDetector supervises the detector of the L2 reorgs of the confirmed txs, if ReorgDetection is enabled
+	reorgDetector *supervisor.Supervisor
+	// dataStreamReader supervises the reader of the L2 blocks from the data stream, if DataStream is enabled
+	dataStreamReader *supervisor.Supervisor
+	// lastStreamedBlock is the last L2 block read from the data stream
+	lastStreamedBlock uint64
+	// pendingChecks holds the schedules of the receipt checks not persisted yet in the pool database, if RetrySchedule
+	// is enabled
+	pendingChecks      map[uint64]types.L2TransactionCheck
+	pendingChecksMutex sync.Mutex
 }
 
 type monitorRequest struct {
 	l2Tx      types.L2Transaction
 	nextRetry time.Time
+	// includedInBlock is set when the block tracker or the data stream reader has found the tx in a L2 block
+	includedInBlock bool
+	// dropCheckedAt is the time of the last check of the tx in the L2 node, if DropDetection is enabled
+	dropCheckedAt time.Time
+	// expired is set when the tx has reached its lifetime and the nonce of the account must be checked, if the
+	// Expiry.Policy is checkNonce
+	expired bool
+	// heapIndex and seq are the position and the sequence number of the request in the retry scheduler
+	heapIndex int
+	seq       uint64
 }
 
-func NewMonitor(cfg Config, poolDB poolDBInterface) *Monitor {
+func NewMonitor(cfg Config, poolDB poolDBInterface, leader leaderInterface) *Monitor {
 	return &Monitor{
 		cfg:              cfg,
 		poolDB:           poolDB,
+		leader:           leader,
+		workers:          supervisor.NewSupervisor("monitor", cfg.Supervisor),
 		requestChan:      make(chan *monitorRequest, cfg.QueueSize),
-		requestRetryList: newMonitorRequestList(),
-		requestRetryCond: sync.NewCond(&sync.Mutex{}),
+		retryScheduler:   newRequestScheduler(),
+		monitored:        make(map[uint64]struct{}),
+		monitoredHashes:  make(map[common.Hash]*monitorRequest),
+		limiter:          limiter.NewLimiter("monitor", cfg.RateLimit),
+		dialL2Node:       rpcclient.DialL2Node,
+		blockTracker:     supervisor.NewSupervisor("monitor-blocks", cfg.Supervisor),
+		batchTracker:     supervisor.NewSupervisor("monitor-batches", cfg.Supervisor),
+		reorgDetector:    supervisor.NewSupervisor("monitor-reorgs", cfg.Supervisor),
+		dataStreamReader: supervisor.NewSupervisor("monitor-datastream", cfg.Supervisor),
+		pendingChecks:    make(map[uint64]types.L2TransactionCheck),
 	}
 }
 
-func (m *Monitor) Start() {
+// Start starts the monitor workers and the background loops. The background loops run until the context is done, the
+// workers run until the monitor is stopped, so they can finish processing the current requests
+func (m *Monitor) Start(ctx context.Context) {
 	log.Infof("starting %d monitor workers", m.cfg.Workers)
+
+	workersCtx, stopWorkers := context.WithCancel(context.Background())
+	m.stopWorkers = stopWorkers
 	for i := 0; i < int(m.cfg.Workers); i++ {
-		go m.startMonitorWorker(i)
+		m.workers.Go(workersCtx, i, m.runMonitorWorker)
+	}
+
+	go m.checkMonitorRequestRetries(ctx)
+
+	if m.cfg.BlockTracking.Enabled {
+		log.Infof("detecting receipts following the new L2 blocks")
+		m.blockTracker.Go(ctx, 0, m.runBlockTracker)
+	}
+
+	if m.cfg.DataStream.Enabled {
+		log.Infof("detecting receipts reading the L2 blocks from the data stream %s", m.cfg.DataStream.Server)
+		m.dataStreamReader.Go(ctx, 0, m.runDataStreamReader)
+	}
+
+	if m.cfg.BatchTracking.Enabled {
+		log.Infof("tracking the virtualization and verification of the L2 batches")
+		m.batchTracker.Go(ctx, 0, m.runBatchTracker)
+	}
+
+	if m.cfg.ReorgDetection.Enabled {
+		log.Infof("detecting L2 reorgs of the txs confirmed in the last %d blocks", m.cfg.ReorgDetection.Depth)
+		m.reorgDetector.Go(ctx, 0, m.runReorgDetector)
 	}
 
-	go m.checkMonitorRequestRetries()
+	if m.cfg.RetrySchedule.Enabled {
+		go m.persistRetrySchedules(ctx)
+	}
+
+	if m.poolDB.OwnerID() != "" {
+		// With leases each instance monitors its own sent txs and the ones not owned by running instances
+		log.Infof("monitoring txs from the pool database")
+		m.monitorL2TransactionsFromPoolDB(ctx)
+	} else {
+		// Without leases the leader monitors the sent txs of the pool database each time it gets the leadership
+		m.leader.OnAcquired(func() {
+			log.Infof("monitoring txs from the pool database")
+			go m.monitorL2TransactionsFromPoolDB(ctx)
+		})
+	}
+
+	if m.cfg.SentTxsCheckInterval.Duration > 0 {
+		go m.checkL2TransactionsToMonitor(ctx)
+	}
 
-	log.Infof("monitoring txs from the pool database")
-	m.monitorL2TransactionsFromPoolDB()
+	if m.cfg.ExpiredTxsCheckInterval.Duration > 0 {
+		go m.checkExpiredL2Transactions(ctx)
+	}
+}
+
+// Stop stops monitoring new txs and waits for the workers to finish the current requests. The txs that are still
+// monitored are kept with sent status in the pool database, so they will be monitored again in the next start, with
+// the persisted schedule of their receipt checks if RetrySchedule is enabled
+func (m *Monitor) Stop(ctx context.Context) error {
+	log.Infof("stopping monitor")
+
+	m.monitoredMutex.Lock()
+	m.stopped = true
+	m.monitoredMutex.Unlock()
+
+	if m.stopWorkers != nil {
+		m.stopWorkers()
+	}
+	err := m.workers.Wait(ctx)
+	if m.cfg.BlockTracking.Enabled {
+		err = errors.Join(err, m.blockTracker.Wait(ctx))
+	}
+	if m.cfg.DataStream.Enabled {
+		err = errors.Join(err, m.dataStreamReader.Wait(ctx))
+	}
+	if m.cfg.BatchTracking.Enabled {
+		err = errors.Join(err, m.batchTracker.Wait(ctx))
+	}
+	if m.cfg.ReorgDetection.Enabled {
+		err = errors.Join(err, m.reorgDetector.Wait(ctx))
+	}
+	if m.cfg.RetrySchedule.Enabled {
+		m.flushRetrySchedules(ctx)
+	}
+
+	m.monitoredMutex.Lock()
+	monitored := len(m.monitored)
+	m.monitoredMutex.Unlock()
+	log.Infof("%d txs are still monitored, they are kept as sent in the pool database for the next start", monitored)
+
+	return err
 }
 
 func (m *Monitor) AddL2Transaction(l2Tx *types.L2Transaction) {
@@ -53,58 +193,287 @@ func (m *Monitor) AddL2Transaction(l2Tx *types.L2Transaction) {
 		l2Tx: *l2Tx,
 	}
 
-	if m.cfg.InitialWaitInterval.Duration > 0 {
+	if !m.trackL2Transaction(request) {
+		log.Debugf("tx %s is already being monitored or the monitor is stopped", l2Tx.Tag())
+		return
+	}
+
+	if stragglerWaitInterval, ok := m.stragglerWaitInterval(); ok {
+		// The receipt is requested when the tx is found in a new L2 block, it's only polled if the tx is not found
+		request.nextRetry = time.Now().Add(stragglerWaitInterval)
+		m.retryScheduler.add(request)
+	} else if m.cfg.InitialWaitInterval.Duration > 0 {
 		request.nextRetry = time.Now().Add(m.cfg.InitialWaitInterval.Duration)
-		m.addRequestToRetryList(request)
+		m.retryScheduler.add(request)
 	} else {
 		m.enqueueMonitorRequest(request)
 	}
 }
 
+// trackL2Transaction adds the tx of the request to the set of monitored txs. Returns false if the tx is already
+// monitored or the monitor is stopped
+func (m *Monitor) trackL2Transaction(request *monitorRequest) bool {
+	m.monitoredMutex.Lock()
+	defer m.monitoredMutex.Unlock()
+
+	if m.stopped {
+		return false
+	}
+	if _, found := m.monitored[request.l2Tx.Id]; found {
+		return false
+	}
+	m.monitored[request.l2Tx.Id] = struct{}{}
+	m.monitoredHashes[common.HexToHash(request.l2Tx.Hash)] = request
+	return true
+}
+
+// untrackL2Transaction removes the tx of the request from the set of monitored txs
+func (m *Monitor) untrackL2Transaction(request *monitorRequest) {
+	m.monitoredMutex.Lock()
+	defer m.monitoredMutex.Unlock()
+
+	delete(m.monitored, request.l2Tx.Id)
+	delete(m.monitoredHashes, common.HexToHash(request.l2Tx.Hash))
+}
+
+// isMonitored returns true if the tx is being monitored
+func (m *Monitor) isMonitored(id uint64) bool {
+	m.monitoredMutex.Lock()
+	defer m.monitoredMutex.Unlock()
+
+	_, found := m.monitored[id]
+	return found
+}
+
+// monitoredRequest returns the request of the monitored tx with the hash
+func (m *Monitor) monitoredRequest(hash common.Hash) (*monitorRequest, bool) {
+	m.monitoredMutex.Lock()
+	defer m.monitoredMutex.Unlock()
+
+	request, found := m.monitoredHashes[hash]
+	return request, found
+}
+
 func (m *Monitor) enqueueMonitorRequest(request *monitorRequest) {
 	log.Debugf("monitor request for tx %s added to the queue channel", request.l2Tx.Tag(), request.l2Tx.Tag())
 	// Enqueue monitorRequest in the channel. We do in a go func to avoid blocking in case the channel buffer is full
 	go func() { m.requestChan <- request }()
 }
 
-func (m *Monitor) startMonitorWorker(workerNum int) {
-	rpcClient, err := ethclient.Dial(m.cfg.L2NodeURL)
+// runMonitorWorker runs a monitor worker. It's run by the workers supervisor, that restarts it if it returns an error
+func (m *Monitor) runMonitorWorker(ctx context.Context, workerNum int, ready func()) error {
+	dialCtx, cancel := context.WithTimeout(ctx, m.cfg.RPCReadTimeout.Duration)
+	defer cancel()
 
+	rpcClient, err := m.dialL2Node(dialCtx, m.cfg.L2NodeURL, m.cfg.L2NodeAuth)
 	if err != nil {
-		log.Errorf("monitor-worker[%03d]: error creating rpc client for %s, err: %v", workerNum, m.cfg.L2NodeURL, err)
-		return
+		return fmt.Errorf("error creating rpc client for %s, err: %v", m.cfg.L2NodeURL, err)
+	}
+	defer rpcClient.Close()
+
+	// Check the L2 node is reachable before start processing requests
+	err = rpcClient.Health(dialCtx)
+	if err != nil {
+		return fmt.Errorf("error connecting to L2 node %s, err: %v", m.cfg.L2NodeURL, err)
+	}
+	chainID, err := rpcClient.ChainID(dialCtx)
+	if err != nil {
+		return fmt.Errorf("error getting chain id from L2 node %s, err: %v", m.cfg.L2NodeURL, err)
+	}
+
+	log.Debugf("monitor-worker[%03d]: started, chain id: %d", workerNum, chainID)
+	ready()
+
+	for {
+		var request *monitorRequest
+		select {
+		case request = <-m.requestChan:
+		case <-ctx.Done():
+			log.Debugf("monitor-worker[%03d]: stopped", workerNum)
+			return nil
+		}
+
+		batch := []*monitorRequest{request}
+		if m.cfg.BatchReceipts.Enabled {
+			batch = m.collectBatch(request)
+		}
+
+		if err := m.limiter.AcquireN(ctx, len(batch)); err != nil {
+			// The worker is stopping, the requests are scheduled to be retried so they are persisted with the schedule
+			for _, batchRequest := range batch {
+				if m.isMonitored(batchRequest.l2Tx.Id) {
+					m.scheduleRequestRetry(batchRequest)
+				}
+			}
+			log.Debugf("monitor-worker[%03d]: stopped", workerNum)
+			return nil
+		}
+
+		// The limiter is released right after the call to the L2 node, as processing the receipts may do other calls
+		// through the limiter (e.g. the drop and expiry checks)
+		start := time.Now()
+		released := false
+		release := func(callErr error) {
+			m.limiter.Release(time.Since(start), callErr)
+			released = true
+		}
+		panicErr := m.workerProcessRequestsSafely(batch, rpcClient, workerNum, release)
+		if !released {
+			m.limiter.Cancel()
+		}
+		if panicErr != nil {
+			return panicErr
+		}
 	}
+}
+
+// workerProcessRequestsSafely processes the monitor requests, calling release with the error of the call to the L2
+// node. If the processing panics, the requests still monitored are scheduled for retry and the panic is returned as
+// error to restart the worker
+func (m *Monitor) workerProcessRequestsSafely(batch []*monitorRequest, rpcClient rpcclient.L2NodeClient, workerNum int, release func(error)) (panicErr error) {
+	defer func() {
+		if r := recover(); r != nil {
+			panicErr = fmt.Errorf("panic processing %d monitor requests: %v\n%s", len(batch), r, debug.Stack())
+			for _, request := range batch {
+				if m.isMonitored(request.l2Tx.Id) {
+					m.scheduleRequestRetry(request)
+				}
+			}
+		}
+	}()
+
+	if m.cfg.BatchReceipts.Enabled {
+		m.workerProcessBatch(batch, rpcClient, workerNum, release)
+	} else {
+		m.workerProcessRequest(batch[0], rpcClient, workerNum, release)
+	}
+
+	return nil
+}
+
+// collectBatch coalesces the queued monitor requests into a batch, until BatchReceipts.MaxSize requests are collected
+// or BatchReceipts.MaxLinger time elapses
+func (m *Monitor) collectBatch(first *monitorRequest) []*monitorRequest {
+	batch := []*monitorRequest{first}
+
+	linger := time.NewTimer(m.cfg.BatchReceipts.MaxLinger.Duration)
+	defer linger.Stop()
 
-	log.Debugf("monitor-worker[%03d]: started", workerNum)
-	for monitorRequest := range m.requestChan {
-		m.workerProcessRequest(monitorRequest, rpcClient, workerNum)
+	for len(batch) < int(m.cfg.BatchReceipts.MaxSize) {
+		select {
+		case request := <-m.requestChan:
+			batch = append(batch, request)
+		case <-linger.C:
+			return batch
+		}
 	}
+
+	return batch
+}
+
+// WaitWorkersAlive waits until at least one monitor worker is alive, up to the Supervisor.StartupTimeout
+func (m *Monitor) WaitWorkersAlive() error {
+	return m.workers.WaitAlive()
+}
+
+// WorkersStatus returns the status of the monitor workers
+func (m *Monitor) WorkersStatus() supervisor.Status {
+	return m.workers.Status()
 }
 
 func (m *Monitor) scheduleRequestRetry(request *monitorRequest) {
-	request.nextRetry = time.Now().Add(m.cfg.RetryWaitInterval.Duration)
-	m.addRequestToRetryList(request)
+	request.nextRetry = time.Now().Add(m.retryWaitInterval(request))
+	request.l2Tx.CheckCount++
+	m.recordRequestSchedule(request)
+	m.retryScheduler.add(request)
 }
 
-func (m *Monitor) addRequestToRetryList(request *monitorRequest) {
-	m.requestRetryList.add(request)
+// retryWaitInterval returns the time to wait before retrying the request. If BlockTracking or DataStream is enabled,
+// the receipts of the txs not found yet in the new L2 blocks are polled after the StragglerWaitInterval
+func (m *Monitor) retryWaitInterval(request *monitorRequest) time.Duration {
+	if stragglerWaitInterval, ok := m.stragglerWaitInterval(); ok && !request.includedInBlock {
+		return stragglerWaitInterval
+	}
+	return m.cfg.RetryWaitInterval.Duration
+}
 
-	if m.requestRetryList.len() == 1 {
-		log.Debugf("signal monitor request retries")
-		m.requestRetryCond.Signal()
+// stragglerWaitInterval returns the StragglerWaitInterval of the source of the new L2 blocks, if BlockTracking or
+// DataStream is enabled
+func (m *Monitor) stragglerWaitInterval() (time.Duration, bool) {
+	if m.cfg.BlockTracking.Enabled {
+		return m.cfg.BlockTracking.StragglerWaitInterval.Duration, true
+	}
+	if m.cfg.DataStream.Enabled {
+		return m.cfg.DataStream.StragglerWaitInterval.Duration, true
 	}
+	return 0, false
 }
 
-func (m *Monitor) workerProcessRequest(request *monitorRequest, rpcClient *ethclient.Client, workerNum int) {
+// workerProcessRequest gets the receipt of the tx and updates its status. The error of the call to the L2 node is passed
+// to release, before the receipt is processed, and returned
+func (m *Monitor) workerProcessRequest(request *monitorRequest, rpcClient rpcclient.L2NodeClient, workerNum int, release func(error)) error {
 	log.Infof("monitor-worker[%03d]: monitoring tx %s", workerNum, request.l2Tx.Tag())
 
 	ctx, cancel := context.WithTimeout(context.Background(), m.cfg.RPCReadTimeout.Duration)
 	defer cancel()
 	receipt, err := rpcClient.TransactionReceipt(ctx, common.HexToHash(request.l2Tx.Hash))
 	log.Debugf("monitor-worker[%03d]: monitoring tx, get receipt ok %s, err:%v", workerNum, request.l2Tx.Tag(), err)
+	release(err)
+	m.processReceipt(request, receipt, err, rpcClient, workerNum)
+
+	return err
+}
+
+// workerProcessBatch gets the receipts of the txs in a single batch request and updates their status. The first error of
+// the calls to the L2 node is passed to release, before the receipts are processed, and returned
+func (m *Monitor) workerProcessBatch(batch []*monitorRequest, rpcClient rpcclient.L2NodeClient, workerNum int, release func(error)) error {
+	log.Debugf("monitor-worker[%03d]: monitoring batch of %d txs", workerNum, len(batch))
+
+	hashes := make([]common.Hash, len(batch))
+	for i, request := range batch {
+		hashes[i] = common.HexToHash(request.l2Tx.Hash)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), m.cfg.RPCReadTimeout.Duration)
+	defer cancel()
+	receipts, errs, err := rpcClient.TransactionReceipts(ctx, hashes)
+	if err != nil {
+		log.Errorf("monitor-worker[%03d]: error getting receipts for batch of %d txs, error: %v", workerNum, len(batch), err)
+
+		// The whole batch request failed, all the monitor requests get the same error
+		receipts = make([]*ethTypes.Receipt, len(batch))
+		errs = make([]error, len(batch))
+		for i := range errs {
+			errs[i] = err
+		}
+	}
+
+	var firstErr error
+	for _, err := range errs {
+		if err != nil && !errors.Is(err, ethereum.NotFound) {
+			firstErr = err
+			break
+		}
+	}
+	release(firstErr)
+
+	for i, request := range batch {
+		m.processReceipt(request, receipts[i], errs[i], rpcClient, workerNum)
+	}
+
+	return firstErr
+}
+
+// processReceipt updates the status of the tx with its receipt, or schedules a retry if the receipt is not available
+// and the tx has not been dropped or expired
+func (m *Monitor) processReceipt(request *monitorRequest, receipt *ethTypes.Receipt, err error, rpcClient rpcclient.L2NodeClient, workerNum int) {
 	if err != nil {
 		if !errors.Is(err, ethereum.NotFound) {
 			log.Errorf("monitor-worker[%03d]: error getting receipt for tx %s, schedule retry, error: %v", workerNum, request.l2Tx.Tag(), err)
+		} else if request.expired && m.checkExpiredL2TransactionNonce(request, rpcClient, workerNum) {
+			return
+		} else if m.isDropCheckDue(request) && m.checkDroppedL2Transaction(request, rpcClient, workerNum) {
+			return
 		} else {
 			log.Debugf("monitor-worker[%03d]: receipt for tx %s still not available, schedule retry", workerNum, request.l2Tx.Tag())
 		}
@@ -115,56 +484,161 @@ func (m *Monitor) workerProcessRequest(request *monitorRequest, rpcClient *ethcl
 			l2TxStatus = types.TxStatusFailed
 		}
 
-		err := m.poolDB.UpdateL2TransactionStatus(context.Background(), request.l2Tx.Id, l2TxStatus, "")
+		err := m.poolDB.UpdateL2TransactionReceipt(context.Background(), request.l2Tx.Id, l2TxStatus, newL2TransactionReceipt(request.l2Tx.Id, receipt))
 		if err != nil {
 			log.Errorf("monitor-worker[%03d]: error updating status for tx %s, schedule retry, error: %v", workerNum, request.l2Tx.Tag(), err)
 			m.scheduleRequestRetry(request)
 		} else {
 			log.Infof("monitor-worker[%03d]: receipt for tx %s received, status: %d", workerNum, request.l2Tx.Tag(), receipt.Status)
-			m.requestRetryList.delete(request)
+			m.retryScheduler.delete(request)
+			m.untrackL2Transaction(request)
 		}
 	}
 }
 
-func (m *Monitor) checkMonitorRequestRetries() {
-	for {
-		if m.requestRetryList.len() > 0 {
-			now := time.Now()
-			request := m.requestRetryList.getByIndex(0)
-			// Check if tx has reached max lifetime
-			if request.l2Tx.ReceivedAt.Add(m.cfg.TxLifeTimeMax.Duration).Before(now) {
-				log.Debugf("monitor tx %s has expired, updating status", request.l2Tx.Tag())
-				err := m.poolDB.UpdateL2TransactionStatus(context.Background(), request.l2Tx.Id, types.TxStatusExpired, "")
-				if err != nil {
-					log.Errorf("error updating tx %s status (%s) in the pool db, error: %v", request.l2Tx.Tag(), types.TxStatusExpired, err)
-				}
-				m.requestRetryList.delete(request)
-			} else if request.nextRetry.Before(now) {
-				log.Debugf("retry monitor tx %s that was schedule to %v", request.l2Tx.Tag(), request.nextRetry)
-				m.requestRetryList.delete(request)
+// newL2TransactionReceipt returns the receipt details of the tx to store in the pool database
+func newL2TransactionReceipt(id uint64, receipt *ethTypes.Receipt) *types.L2TransactionReceipt {
+	l2TxReceipt := &types.L2TransactionReceipt{
+		TxId:              id,
+		Status:            receipt.Status,
+		BlockHash:         receipt.BlockHash.Hex(),
+		TxIndex:           uint64(receipt.TransactionIndex),
+		GasUsed:           receipt.GasUsed,
+		EffectiveGasPrice: receipt.EffectiveGasPrice,
+		LogsBloom:         receipt.Bloom.Bytes(),
+	}
+	if receipt.BlockNumber != nil {
+		l2TxReceipt.BlockNumber = receipt.BlockNumber.Uint64()
+	}
+	if receipt.ContractAddress != (common.Address{}) {
+		l2TxReceipt.ContractAddress = receipt.ContractAddress.Hex()
+	}
+
+	return l2TxReceipt
+}
+
+// checkMonitorRequestRetries takes the scheduled requests when their retry time is reached and enqueues them, or applies
+// the expiry policy if their tx has reached its lifetime
+func (m *Monitor) checkMonitorRequestRetries(ctx context.Context) {
+	for ctx.Err() == nil {
+		request, found := m.retryScheduler.peek()
+		if !found {
+			// wait for new monitorRequest to retry
+			log.Debugf("waiting processing monitor txs requests retries")
+			select {
+			case <-ctx.Done():
+			case <-m.retryScheduler.wakeup:
+			}
+			continue
+		}
+
+		now := time.Now()
+		// Check if tx has reached its lifetime
+		if m.expiresAt(&request.l2Tx).Before(now) {
+			if !m.retryScheduler.delete(request) {
+				// The request has been taken by the block tracker or the data stream reader
+				continue
+			}
+			m.expireMonitorRequest(request)
+		} else if request.nextRetry.Before(now) {
+			log.Debugf("retry monitor tx %s that was schedule to %v", request.l2Tx.Tag(), request.nextRetry)
+			// The request is not enqueued if it has been taken by the block tracker or the data stream reader
+			if m.retryScheduler.delete(request) {
 				m.enqueueMonitorRequest(request)
-			} else {
-				sleepTime := request.nextRetry.Sub(now)
-				time.Sleep(sleepTime)
 			}
 		} else {
-			// wait for new monitorRequest to retry
-			log.Debugf("waiting processing monitor txs requests retries")
-			m.requestRetryCond.L.Lock()
-			m.requestRetryCond.Wait()
-			m.requestRetryCond.L.Unlock()
-			log.Debugf("continuing processing monitor txs requests retries")
+			// Wait until the retry time of the request, or until a request with an earlier retry time is added
+			timer := time.NewTimer(request.nextRetry.Sub(now))
+			select {
+			case <-ctx.Done():
+			case <-timer.C:
+			case <-m.retryScheduler.wakeup:
+			}
+			timer.Stop()
 		}
 	}
 }
 
-func (m *Monitor) monitorL2TransactionsFromPoolDB() {
-	l2Txs, err := m.poolDB.GetL2TransactionsToMonitor(context.Background())
+func (m *Monitor) monitorL2TransactionsFromPoolDB(ctx context.Context) {
+	l2Txs, err := m.poolDB.GetL2TransactionsToMonitor(ctx)
 	if err != nil {
 		log.Errorf("error when getting txs to monitor from the pool database, error: %v", err)
 	}
 
 	for _, l2Tx := range l2Txs {
-		m.AddL2Transaction(l2Tx)
+		m.restoreL2Transaction(l2Tx)
+	}
+}
+
+// checkL2TransactionsToMonitor periodically looks for sent txs in the pool database that are not monitored, for example
+// the txs that were owned by a pool-manager instance that is not running anymore. Without leases all the sent txs are
+// loaded, so only the leader instance checks them
+func (m *Monitor) checkL2TransactionsToMonitor(ctx context.Context) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(m.cfg.SentTxsCheckInterval.Duration):
+		}
+
+		if m.poolDB.OwnerID() == "" && !m.leader.IsLeader() {
+			continue
+		}
+
+		m.monitorL2TransactionsFromPoolDB(ctx)
+	}
+}
+
+// checkExpiredL2Transactions periodically applies the expiry policy to the sent txs in the pool database that have
+// reached their lifetime, including the txs monitored by other pool-manager instances. The txs are resent if the
+// Expiry.Policy is resend, otherwise they are expired, as the nonce can only be checked for the txs monitored by this
+// instance. With the checkNonce policy the txs monitored by this instance are skipped, so their nonce is checked when
+// they expire. It only runs in the leader instance
+func (m *Monitor) checkExpiredL2Transactions(ctx context.Context) {
+	fromLastSent := m.cfg.Expiry.LifetimeFrom == LifetimeFromLastSent
+	maxResends := uint64(0)
+	if m.cfg.Expiry.Policy == ExpiryPolicyResend {
+		maxResends = m.cfg.Expiry.MaxResends
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(m.cfg.ExpiredTxsCheckInterval.Duration):
+		}
+
+		if !m.leader.IsLeader() {
+			continue
+		}
+
+		var excludedIDs []uint64
+		if m.cfg.Expiry.Policy == ExpiryPolicyCheckNonce {
+			excludedIDs = m.monitoredIDs()
+		}
+
+		expired, resent, err := m.poolDB.UpdateExpiredL2Transactions(ctx, m.cfg.TxLifeTimeMax.Duration, fromLastSent, maxResends, excludedIDs)
+		if err != nil {
+			log.Errorf("error updating expired txs in the pool db, error: %v", err)
+			continue
+		}
+		if expired > 0 {
+			log.Infof("%d txs have expired", expired)
+		}
+		if resent > 0 {
+			log.Infof("%d expired txs have been moved to resend", resent)
+		}
+	}
+}
+
+// monitoredIDs returns the ids of the txs monitored by this instance
+func (m *Monitor) monitoredIDs() []uint64 {
+	m.monitoredMutex.Lock()
+	defer m.monitoredMutex.Unlock()
+
+	ids := make([]uint64, 0, len(m.monitored))
+	for id := range m.monitored {
+		ids = append(ids, id)
 	}
+	return ids
 }
@@ -0,0 +1,518 @@
+package monitor
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/0xPolygonHermez/zkevm-pool-manager/config/types"
+	"github.com/0xPolygonHermez/zkevm-pool-manager/rpcclient"
+	"github.com/0xPolygonHermez/zkevm-pool-manager/supervisor"
+	poolTypes "github.com/0xPolygonHermez/zkevm-pool-manager/types"
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	ethTypes "github.com/ethereum/go-ethereum/core/types"
+	"github.com/jackc/pgx/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakePoolDB is an in-memory pool database that records the status of the txs
+type fakePoolDB struct {
+	statuses map[uint64]string
+	receipts map[uint64]*poolTypes.L2TransactionReceipt
+	// blocks holds the L2 blocks of the confirmed txs without batch number
+	blocks []uint64
+	// batches holds the batch numbers set for the L2 blocks
+	batches map[uint64]uint64
+	// batchState holds the last virtual and verified batches used to update the txs
+	batchState [2]uint64
+	// reorgBlocks holds the L2 blocks of the confirmed txs to check reorgs
+	reorgBlocks []*poolTypes.L2Block
+	// reorgs holds the canonical hash and the new status of the reorged blocks
+	reorgs map[uint64][2]string
+	// checkpoints holds the last L2 block read from each data stream server
+	checkpoints map[string]uint64
+	// ownerID is the lease owner of the instance, empty without leases
+	ownerID string
+	// replacedBy holds the hash of the winning tx returned for each replaced tx
+	replacedBy map[uint64]string
+	// checks holds the persisted schedules of the receipt checks
+	checks []poolTypes.L2TransactionCheck
+	// expiryExcludedIDs holds the ids of the txs skipped by the last check of the expired txs
+	expiryExcludedIDs []uint64
+	mutex             sync.Mutex
+}
+
+func (p *fakePoolDB) UpdateL2TransactionStatus(ctx context.Context, id uint64, newStatus string, errorMsg string) error {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	p.statuses[id] = newStatus
+	return nil
+}
+
+func (p *fakePoolDB) UpdateL2TransactionReceipt(ctx context.Context, id uint64, newStatus string, receipt *poolTypes.L2TransactionReceipt) error {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	p.statuses[id] = newStatus
+	if p.receipts != nil {
+		p.receipts[id] = receipt
+	}
+	return nil
+}
+
+func (p *fakePoolDB) status(id uint64) string {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	return p.statuses[id]
+}
+
+func (p *fakePoolDB) GetL2TransactionsToMonitor(ctx context.Context) ([]*poolTypes.L2Transaction, error) {
+	return nil, nil
+}
+
+func (p *fakePoolDB) UpdateExpiredL2Transactions(ctx context.Context, maxLifetime time.Duration, fromLastSent bool, maxResends uint64, excludedIDs []uint64) (int64, int64, error) {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	p.expiryExcludedIDs = excludedIDs
+	return 0, 0, nil
+}
+
+func (p *fakePoolDB) ResendExpiredL2Transaction(ctx context.Context, id uint64) (bool, error) {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	p.statuses[id] = poolTypes.TxStatusResend
+	return true, nil
+}
+
+func (p *fakePoolDB) GetL2BlocksWithoutBatchNumber(ctx context.Context, limit uint64) ([]uint64, error) {
+	return p.blocks, nil
+}
+
+func (p *fakePoolDB) UpdateL2BlockBatchNumber(ctx context.Context, blockNumber uint64, batchNumber uint64) error {
+	p.batches[blockNumber] = batchNumber
+	return nil
+}
+
+func (p *fakePoolDB) UpdateL2TransactionsBatchStatus(ctx context.Context, virtualBatch uint64, verifiedBatch uint64) (int64, int64, error) {
+	p.batchState = [2]uint64{virtualBatch, verifiedBatch}
+	return 0, 0, nil
+}
+
+func (p *fakePoolDB) GetL2BlocksToCheckReorg(ctx context.Context, fromBlock uint64) ([]*poolTypes.L2Block, error) {
+	return p.reorgBlocks, nil
+}
+
+func (p *fakePoolDB) ReorgL2Transactions(ctx context.Context, block *poolTypes.L2Block, canonicalHash string, newStatus string) ([]*poolTypes.L2Transaction, error) {
+	p.reorgs[block.Number] = [2]string{canonicalHash, newStatus}
+	return []*poolTypes.L2Transaction{{Id: block.Number, Hash: block.Hash, ReceivedAt: time.Now()}}, nil
+}
+
+func (p *fakePoolDB) GetDataStreamCheckpoint(ctx context.Context, server string) (uint64, error) {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	blockNumber, found := p.checkpoints[server]
+	if !found {
+		return 0, pgx.ErrNoRows
+	}
+	return blockNumber, nil
+}
+
+func (p *fakePoolDB) UpdateDataStreamCheckpoint(ctx context.Context, server string, blockNumber uint64) error {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	p.checkpoints[server] = blockNumber
+	return nil
+}
+
+func (p *fakePoolDB) checkpoint(server string) uint64 {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	return p.checkpoints[server]
+}
+
+func (p *fakePoolDB) UpdateL2TransactionReplaced(ctx context.Context, id uint64) (string, error) {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	p.statuses[id] = poolTypes.TxStatusReplaced
+	return p.replacedBy[id], nil
+}
+
+func (p *fakePoolDB) OwnerID() string {
+	return p.ownerID
+}
+
+func (p *fakePoolDB) UpdateL2TransactionsNextCheck(ctx context.Context, checks []poolTypes.L2TransactionCheck) (int64, error) {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	p.checks = append(p.checks, checks...)
+	return int64(len(checks)), nil
+}
+
+// fakeLeader is the leader unless follower is set
+type fakeLeader struct {
+	follower bool
+}
+
+func (l *fakeLeader) IsLeader() bool {
+	return !l.follower
+}
+
+func (l *fakeLeader) OnAcquired(fn func()) {
+	fn()
+}
+
+func TestWorkerProcessRequest(t *testing.T) {
+	poolDB := &fakePoolDB{statuses: make(map[uint64]string), receipts: make(map[uint64]*poolTypes.L2TransactionReceipt)}
+	m := NewMonitor(Config{RPCReadTimeout: types.NewDuration(time.Second), RetryWaitInterval: types.NewDuration(time.Minute)}, poolDB, &fakeLeader{})
+
+	l2NodeClient := rpcclient.NewFakeL2NodeClient(1001)
+	l2NodeClient.SetReceipt(common.HexToHash("0x01"), &ethTypes.Receipt{
+		Status:            ethTypes.ReceiptStatusSuccessful,
+		BlockNumber:       big.NewInt(10),
+		BlockHash:         common.HexToHash("0xb1"),
+		TransactionIndex:  2,
+		GasUsed:           21000,
+		EffectiveGasPrice: big.NewInt(1000000000),
+		ContractAddress:   common.HexToAddress("0xc1"),
+	})
+	l2NodeClient.SetReceipt(common.HexToHash("0x02"), &ethTypes.Receipt{Status: ethTypes.ReceiptStatusFailed, BlockNumber: big.NewInt(11)})
+
+	requests := []*monitorRequest{
+		{l2Tx: poolTypes.L2Transaction{Id: 1, Hash: "0x01"}},
+		{l2Tx: poolTypes.L2Transaction{Id: 2, Hash: "0x02"}},
+		{l2Tx: poolTypes.L2Transaction{Id: 3, Hash: "0x03"}},
+	}
+	for _, request := range requests {
+		m.trackL2Transaction(request)
+		m.workerProcessRequest(request, l2NodeClient, 0, func(error) {})
+	}
+
+	assert.Equal(t, map[uint64]string{1: poolTypes.TxStatusConfirmed, 2: poolTypes.TxStatusFailed}, poolDB.statuses)
+
+	// The receipt details are stored with the status
+	receipt := poolDB.receipts[1]
+	assert.Equal(t, uint64(1), receipt.TxId)
+	assert.Equal(t, uint64(10), receipt.BlockNumber)
+	assert.Equal(t, common.HexToHash("0xb1").Hex(), receipt.BlockHash)
+	assert.Equal(t, uint64(2), receipt.TxIndex)
+	assert.Equal(t, uint64(21000), receipt.GasUsed)
+	assert.Equal(t, big.NewInt(1000000000), receipt.EffectiveGasPrice)
+	assert.Equal(t, common.HexToAddress("0xc1").Hex(), receipt.ContractAddress)
+	assert.Len(t, receipt.LogsBloom, ethTypes.BloomByteLength)
+	assert.Equal(t, uint64(0), poolDB.receipts[2].Status)
+	assert.Empty(t, poolDB.receipts[2].ContractAddress)
+
+	// Receipt of tx 3 is not available yet, so it's kept monitored and a retry is scheduled
+	assert.Equal(t, 1, m.retryScheduler.len())
+	assert.Equal(t, map[uint64]struct{}{3: {}}, m.monitored)
+}
+
+func TestBlockTracking(t *testing.T) {
+	for _, subscribe := range []bool{true, false} {
+		l2NodeClient := rpcclient.NewFakeL2NodeClient(1001)
+		if !subscribe {
+			l2NodeClient.DisableSubscriptions()
+		}
+		l2NodeClient.AddBlock()
+
+		poolDB := &fakePoolDB{statuses: make(map[uint64]string)}
+		m := NewMonitor(Config{
+			Workers:           1,
+			QueueSize:         10,
+			RPCReadTimeout:    types.NewDuration(time.Second),
+			RetryWaitInterval: types.NewDuration(time.Minute),
+			TxLifeTimeMax:     types.NewDuration(time.Hour),
+			Supervisor: supervisor.Config{
+				RestartInitialBackoff: types.NewDuration(10 * time.Millisecond),
+				RestartMaxBackoff:     types.NewDuration(10 * time.Millisecond),
+				StartupTimeout:        types.NewDuration(500 * time.Millisecond),
+			},
+			BlockTracking: BlockTrackingConfig{
+				Enabled:               true,
+				PollInterval:          types.NewDuration(10 * time.Millisecond),
+				StragglerWaitInterval: types.NewDuration(time.Minute),
+			},
+		}, poolDB, &fakeLeader{})
+		m.dialL2Node = func(ctx context.Context, url string, cfg rpcclient.Config) (rpcclient.L2NodeClient, error) {
+			return l2NodeClient, nil
+		}
+
+		ctx, cancel := context.WithCancel(context.Background())
+		m.Start(ctx)
+		require.NoError(t, m.WaitWorkersAlive())
+		require.NoError(t, m.blockTracker.WaitAlive())
+
+		m.AddL2Transaction(&poolTypes.L2Transaction{Id: 1, Hash: "0x01", ReceivedAt: time.Now()})
+		m.AddL2Transaction(&poolTypes.L2Transaction{Id: 2, Hash: "0x02", ReceivedAt: time.Now()})
+		l2NodeClient.SetReceipt(common.HexToHash("0x01"), &ethTypes.Receipt{Status: ethTypes.ReceiptStatusSuccessful})
+		l2NodeClient.AddBlock(common.HexToHash("0xaa"), common.HexToHash("0x01"))
+
+		// Only the receipt of the tx included in the new block is requested, the other tx waits to be polled as a straggler
+		require.Eventually(t, func() bool { return poolDB.status(1) == poolTypes.TxStatusConfirmed }, time.Second, 10*time.Millisecond, "subscribe: %v", subscribe)
+		assert.Equal(t, 1, l2NodeClient.ReceiptCalls(), "subscribe: %v", subscribe)
+		assert.Empty(t, poolDB.status(2), "subscribe: %v", subscribe)
+		assert.Equal(t, 1, m.retryScheduler.len(), "subscribe: %v", subscribe)
+
+		cancel()
+		stopCtx, stopCancel := context.WithTimeout(context.Background(), time.Second)
+		require.NoError(t, m.Stop(stopCtx))
+		stopCancel()
+	}
+}
+
+func TestWorkerProcessBatch(t *testing.T) {
+	poolDB := &fakePoolDB{statuses: make(map[uint64]string)}
+	cfg := Config{
+		RPCReadTimeout:    types.NewDuration(time.Second),
+		RetryWaitInterval: types.NewDuration(time.Minute),
+		BatchReceipts:     BatchReceiptsConfig{Enabled: true, MaxSize: 10, MaxLinger: types.NewDuration(10 * time.Millisecond)},
+	}
+	m := NewMonitor(cfg, poolDB, &fakeLeader{})
+
+	l2NodeClient := rpcclient.NewFakeL2NodeClient(1001)
+	l2NodeClient.SetReceipt(common.HexToHash("0x01"), &ethTypes.Receipt{Status: ethTypes.ReceiptStatusSuccessful})
+	l2NodeClient.SetReceipt(common.HexToHash("0x02"), &ethTypes.Receipt{Status: ethTypes.ReceiptStatusFailed})
+
+	requests := []*monitorRequest{
+		{l2Tx: poolTypes.L2Transaction{Id: 1, Hash: "0x01"}},
+		{l2Tx: poolTypes.L2Transaction{Id: 2, Hash: "0x02"}},
+		{l2Tx: poolTypes.L2Transaction{Id: 3, Hash: "0x03"}},
+	}
+	for _, request := range requests {
+		m.trackL2Transaction(request)
+	}
+	assert.NoError(t, m.workerProcessBatch(requests, l2NodeClient, 0, func(error) {}))
+
+	// The receipts are requested in a single call and mapped back to each tx
+	assert.Equal(t, 1, l2NodeClient.ReceiptBatchCalls())
+	assert.Equal(t, 0, l2NodeClient.ReceiptCalls())
+	assert.Equal(t, map[uint64]string{1: poolTypes.TxStatusConfirmed, 2: poolTypes.TxStatusFailed}, poolDB.statuses)
+	assert.Equal(t, 1, m.retryScheduler.len())
+	assert.Equal(t, map[uint64]struct{}{3: {}}, m.monitored)
+
+	// If the batch request fails all the txs are scheduled for retry
+	l2NodeClient.SetHealthError(errors.New("connection refused"))
+	request4 := &monitorRequest{l2Tx: poolTypes.L2Transaction{Id: 4, Hash: "0x04"}}
+	m.trackL2Transaction(request4)
+	m.retryScheduler.delete(requests[2])
+	assert.EqualError(t, m.workerProcessBatch([]*monitorRequest{requests[2], request4}, l2NodeClient, 0, func(error) {}), "connection refused")
+	assert.Equal(t, 2, m.retryScheduler.len())
+}
+
+func TestUpdateL2TransactionsBatchStatus(t *testing.T) {
+	poolDB := &fakePoolDB{statuses: make(map[uint64]string), blocks: []uint64{10, 11, 12}, batches: make(map[uint64]uint64)}
+	cfg := Config{
+		RPCReadTimeout: types.NewDuration(time.Second),
+		BatchTracking:  BatchTrackingConfig{Enabled: true, CheckInterval: types.NewDuration(time.Minute), MaxBlocksPerCheck: 10},
+	}
+	m := NewMonitor(cfg, poolDB, &fakeLeader{})
+
+	l2NodeClient := rpcclient.NewFakeL2NodeClient(1001)
+	l2NodeClient.SetBlockBatch(10, 5)
+	l2NodeClient.SetBlockBatch(11, 5)
+	l2NodeClient.SetBatchState(5, 4)
+
+	require.NoError(t, m.updateL2TransactionsBatchStatus(context.Background(), l2NodeClient))
+
+	// The batch of the block 12 is not known yet by the L2 node, it's requested again in the next check
+	assert.Equal(t, map[uint64]uint64{10: 5, 11: 5}, poolDB.batches)
+	assert.Equal(t, [2]uint64{5, 4}, poolDB.batchState)
+
+	// The errors of the L2 node restart the batch tracker
+	l2NodeClient.SetHealthError(errors.New("connection refused"))
+	assert.Error(t, m.updateL2TransactionsBatchStatus(context.Background(), l2NodeClient))
+}
+
+func TestCheckL2Reorgs(t *testing.T) {
+	l2NodeClient := rpcclient.NewFakeL2NodeClient(1001)
+	for i := 0; i < 5; i++ {
+		l2NodeClient.AddBlock()
+	}
+	canonicalHash := func(number uint64) string { return common.BigToHash(new(big.Int).SetUint64(number)).Hex() }
+
+	poolDB := &fakePoolDB{statuses: make(map[uint64]string), reorgs: make(map[uint64][2]string)}
+	poolDB.reorgBlocks = []*poolTypes.L2Block{
+		{Number: 3, Hash: canonicalHash(3)},
+		{Number: 4, Hash: common.HexToHash("0xdead").Hex()},
+		{Number: 7, Hash: common.HexToHash("0xbeef").Hex()},
+	}
+	cfg := Config{
+		RPCReadTimeout:      types.NewDuration(time.Second),
+		InitialWaitInterval: types.NewDuration(time.Minute),
+		ReorgDetection:      ReorgDetectionConfig{Enabled: true, Depth: 10, CheckInterval: types.NewDuration(time.Minute)},
+	}
+	m := NewMonitor(cfg, poolDB, &fakeLeader{})
+
+	require.NoError(t, m.checkL2Reorgs(context.Background(), l2NodeClient))
+
+	// The block 4 has a different hash and the block 7 doesn't exist anymore, their txs are monitored again
+	assert.Equal(t, map[uint64][2]string{
+		4: {canonicalHash(4), poolTypes.TxStatusSent},
+		7: {"", poolTypes.TxStatusSent},
+	}, poolDB.reorgs)
+	assert.Equal(t, map[uint64]struct{}{4: {}, 7: {}}, m.monitored)
+
+	// With Resend enabled the reorged txs are left to the sender
+	m = NewMonitor(Config{RPCReadTimeout: types.NewDuration(time.Second), ReorgDetection: ReorgDetectionConfig{Depth: 10, Resend: true}}, poolDB, &fakeLeader{})
+	poolDB.reorgs = make(map[uint64][2]string)
+	l2NodeClient.SetBlockHash(3, common.HexToHash("0xf0"))
+	require.NoError(t, m.checkL2Reorgs(context.Background(), l2NodeClient))
+	assert.Equal(t, poolTypes.TxStatusResend, poolDB.reorgs[3][1])
+	assert.Empty(t, m.monitored)
+}
+
+func TestCheckDroppedL2Transactions(t *testing.T) {
+	poolDB := &fakePoolDB{statuses: make(map[uint64]string), replacedBy: map[uint64]string{3: "0xaa"}}
+	m := NewMonitor(Config{
+		RPCReadTimeout:    types.NewDuration(time.Second),
+		RetryWaitInterval: types.NewDuration(time.Minute),
+		DropDetection:     DropDetectionConfig{Enabled: true, CheckAfter: types.NewDuration(time.Minute)},
+	}, poolDB, &fakeLeader{})
+
+	account := common.HexToAddress("0x01")
+	l2NodeClient := rpcclient.NewFakeL2NodeClient(1001)
+	l2NodeClient.SetNonce(account, 4)
+	l2NodeClient.SetPendingTransaction(common.HexToHash("0x02"), true)
+
+	sentAt := time.Now().Add(-2 * time.Minute)
+	requests := []*monitorRequest{
+		// Sent recently, it's not checked yet
+		{l2Tx: poolTypes.L2Transaction{Id: 1, Hash: "0x01", FromAddress: account.Hex(), Nonce: 1, LastSentAt: time.Now()}},
+		// Still known by the L2 node
+		{l2Tx: poolTypes.L2Transaction{Id: 2, Hash: "0x02", FromAddress: account.Hex(), Nonce: 2, LastSentAt: sentAt}},
+		// The account nonce has moved past the tx nonce
+		{l2Tx: poolTypes.L2Transaction{Id: 3, Hash: "0x03", FromAddress: account.Hex(), Nonce: 3, LastSentAt: sentAt}},
+		// The account nonce has not reached the tx nonce
+		{l2Tx: poolTypes.L2Transaction{Id: 4, Hash: "0x04", FromAddress: account.Hex(), Nonce: 4, LastSentAt: sentAt}},
+	}
+	for _, request := range requests {
+		m.trackL2Transaction(request)
+		m.processReceipt(request, nil, ethereum.NotFound, l2NodeClient, 0)
+	}
+
+	assert.Equal(t, map[uint64]string{3: poolTypes.TxStatusReplaced, 4: poolTypes.TxStatusResend}, poolDB.statuses)
+	assert.Equal(t, map[uint64]struct{}{1: {}, 2: {}}, m.monitored)
+	assert.Equal(t, 2, m.retryScheduler.len())
+
+	// The tx still known is not checked again until CheckAfter since the last check
+	assert.True(t, requests[0].dropCheckedAt.IsZero())
+	assert.False(t, requests[1].dropCheckedAt.IsZero())
+	assert.False(t, m.isDropCheckDue(requests[1]))
+}
+
+func TestExpiryPolicies(t *testing.T) {
+	receivedAt := time.Now().Add(-time.Hour)
+	sentAt := time.Now().Add(-time.Minute)
+
+	m := NewMonitor(Config{TxLifeTimeMax: types.NewDuration(30 * time.Minute)}, nil, &fakeLeader{})
+	assert.Equal(t, receivedAt.Add(30*time.Minute), m.expiresAt(&poolTypes.L2Transaction{ReceivedAt: receivedAt, LastSentAt: sentAt}))
+	// The lifetime set by the client is capped to the TxLifeTimeMax
+	assert.Equal(t, receivedAt.Add(5*time.Minute), m.expiresAt(&poolTypes.L2Transaction{ReceivedAt: receivedAt, Lifetime: 5 * time.Minute}))
+	assert.Equal(t, receivedAt.Add(30*time.Minute), m.expiresAt(&poolTypes.L2Transaction{ReceivedAt: receivedAt, Lifetime: time.Hour}))
+	// The lifetime of the txs requeued from the dead-letter store is counted from the requeue
+	assert.Equal(t, sentAt.Add(30*time.Minute), m.expiresAt(&poolTypes.L2Transaction{ReceivedAt: receivedAt, RequeuedAt: sentAt}))
+
+	m.cfg.Expiry.LifetimeFrom = LifetimeFromLastSent
+	assert.Equal(t, sentAt.Add(30*time.Minute), m.expiresAt(&poolTypes.L2Transaction{ReceivedAt: receivedAt, LastSentAt: sentAt}))
+	assert.Equal(t, receivedAt.Add(30*time.Minute), m.expiresAt(&poolTypes.L2Transaction{ReceivedAt: receivedAt}))
+
+	// The resend policy resends the txs up to MaxResends times
+	poolDB := &fakePoolDB{statuses: make(map[uint64]string)}
+	m = NewMonitor(Config{Expiry: ExpiryConfig{Policy: ExpiryPolicyResend, MaxResends: 2}}, poolDB, &fakeLeader{})
+	for _, l2Tx := range []poolTypes.L2Transaction{{Id: 1, Hash: "0x01", ExpiryResends: 1}, {Id: 2, Hash: "0x02", ExpiryResends: 2}} {
+		request := &monitorRequest{l2Tx: l2Tx}
+		m.trackL2Transaction(request)
+		m.expireMonitorRequest(request)
+	}
+	assert.Equal(t, map[uint64]string{1: poolTypes.TxStatusResend, 2: poolTypes.TxStatusExpired}, poolDB.statuses)
+	assert.Empty(t, m.monitored)
+
+	// The checkNonce policy replaces the txs whose nonce has been used, and expires the rest
+	account := common.HexToAddress("0x01")
+	l2NodeClient := rpcclient.NewFakeL2NodeClient(1001)
+	l2NodeClient.SetNonce(account, 4)
+
+	poolDB = &fakePoolDB{statuses: make(map[uint64]string)}
+	m = NewMonitor(Config{RPCReadTimeout: types.NewDuration(time.Second), QueueSize: 2, Expiry: ExpiryConfig{Policy: ExpiryPolicyCheckNonce}}, poolDB, &fakeLeader{})
+	for _, l2Tx := range []poolTypes.L2Transaction{{Id: 3, Hash: "0x03", FromAddress: account.Hex(), Nonce: 3}, {Id: 4, Hash: "0x04", FromAddress: account.Hex(), Nonce: 4}} {
+		request := &monitorRequest{l2Tx: l2Tx}
+		m.trackL2Transaction(request)
+		m.expireMonitorRequest(request)
+		assert.True(t, (<-m.requestChan).expired)
+		m.processReceipt(request, nil, ethereum.NotFound, l2NodeClient, 0)
+	}
+	assert.Equal(t, map[uint64]string{3: poolTypes.TxStatusReplaced, 4: poolTypes.TxStatusExpired}, poolDB.statuses)
+	assert.Empty(t, m.monitored)
+
+	// The nonce has moved past the tx nonce because the tx itself has been included in a block, then it's confirmed
+	l2NodeClient.SetReceipt(common.HexToHash("0x02"), &ethTypes.Receipt{Status: ethTypes.ReceiptStatusSuccessful})
+	request := &monitorRequest{l2Tx: poolTypes.L2Transaction{Id: 2, Hash: "0x02", FromAddress: account.Hex(), Nonce: 2}}
+	m.trackL2Transaction(request)
+	m.expireMonitorRequest(request)
+	m.processReceipt(<-m.requestChan, nil, ethereum.NotFound, l2NodeClient, 0)
+	assert.Equal(t, poolTypes.TxStatusConfirmed, poolDB.status(2))
+	assert.Empty(t, m.monitored)
+
+	// The followers just stop monitoring the expired txs
+	poolDB = &fakePoolDB{statuses: make(map[uint64]string)}
+	m = NewMonitor(Config{RPCReadTimeout: types.NewDuration(time.Second), QueueSize: 1, Expiry: ExpiryConfig{Policy: ExpiryPolicyCheckNonce}}, poolDB, &fakeLeader{follower: true})
+	request = &monitorRequest{l2Tx: poolTypes.L2Transaction{Id: 3, Hash: "0x03", FromAddress: account.Hex(), Nonce: 3}}
+	m.trackL2Transaction(request)
+	m.expireMonitorRequest(request)
+	m.processReceipt(<-m.requestChan, nil, ethereum.NotFound, l2NodeClient, 0)
+	assert.Empty(t, poolDB.statuses)
+	assert.Empty(t, m.monitored)
+
+	// The check of the expired txs in the pool database skips the txs whose nonce is checked by this instance
+	m = NewMonitor(Config{ExpiredTxsCheckInterval: types.NewDuration(10 * time.Millisecond), Expiry: ExpiryConfig{Policy: ExpiryPolicyCheckNonce}}, poolDB, &fakeLeader{})
+	m.trackL2Transaction(&monitorRequest{l2Tx: poolTypes.L2Transaction{Id: 5, Hash: "0x05"}})
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go m.checkExpiredL2Transactions(ctx)
+	require.Eventually(t, func() bool {
+		poolDB.mutex.Lock()
+		defer poolDB.mutex.Unlock()
+		return len(poolDB.expiryExcludedIDs) == 1 && poolDB.expiryExcludedIDs[0] == 5
+	}, time.Second, 10*time.Millisecond)
+}
+
+func TestRetrySchedule(t *testing.T) {
+	poolDB := &fakePoolDB{statuses: make(map[uint64]string)}
+	m := NewMonitor(Config{
+		RetryWaitInterval: types.NewDuration(time.Minute),
+		RetrySchedule:     RetryScheduleConfig{Enabled: true, FlushInterval: types.NewDuration(time.Second), RestoreJitter: types.NewDuration(time.Minute)},
+	}, poolDB, &fakeLeader{})
+
+	now := time.Now()
+	nextCheckAt := now.Add(10 * time.Second)
+	m.restoreL2Transaction(&poolTypes.L2Transaction{Id: 1, Hash: "0x01", NextCheckAt: nextCheckAt, CheckCount: 5})
+	m.restoreL2Transaction(&poolTypes.L2Transaction{Id: 2, Hash: "0x02", NextCheckAt: now.Add(-time.Hour), CheckCount: 2})
+	m.restoreL2Transaction(&poolTypes.L2Transaction{Id: 3, Hash: "0x03"})
+	require.Equal(t, 3, m.retryScheduler.len())
+
+	// The persisted schedule is kept, the overdue checks are spread over the RestoreJitter
+	for _, request := range m.retryScheduler.scheduled() {
+		if request.l2Tx.Id == 1 {
+			assert.Equal(t, nextCheckAt, request.nextRetry)
+			continue
+		}
+		assert.False(t, request.nextRetry.Before(now))
+		assert.True(t, request.nextRetry.Before(now.Add(time.Minute)))
+	}
+
+	// The new schedule of the retried request is persisted in the next flush
+	request, _ := m.retryScheduler.peek()
+	require.True(t, m.retryScheduler.delete(request))
+	m.scheduleRequestRetry(request)
+	m.flushRetrySchedules(context.Background())
+	m.flushRetrySchedules(context.Background())
+
+	require.Len(t, poolDB.checks, 1)
+	assert.Equal(t, request.l2Tx.Id, poolDB.checks[0].TxId)
+	assert.Equal(t, request.nextRetry, poolDB.checks[0].NextCheckAt)
+	assert.Equal(t, request.l2Tx.CheckCount, poolDB.checks[0].CheckCount)
+}
@@ -0,0 +1,122 @@
+package monitor
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/0xPolygonHermez/zkevm-pool-manager/log"
+	"github.com/0xPolygonHermez/zkevm-pool-manager/rpcclient"
+	"github.com/0xPolygonHermez/zkevm-pool-manager/types"
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// runReorgDetector periodically checks the blocks of the recently confirmed txs are still canonical in the L2 node. Only
+// the leader instance checks the txs. It's run by the reorg detector supervisor, that restarts it if it returns an error
+func (m *Monitor) runReorgDetector(ctx context.Context, workerNum int, ready func()) error {
+	dialCtx, cancel := context.WithTimeout(ctx, m.cfg.RPCReadTimeout.Duration)
+	defer cancel()
+
+	rpcClient, err := m.dialL2Node(dialCtx, m.cfg.L2NodeURL, m.cfg.L2NodeAuth)
+	if err != nil {
+		return fmt.Errorf("error creating rpc client for %s, err: %v", m.cfg.L2NodeURL, err)
+	}
+	defer rpcClient.Close()
+
+	err = rpcClient.Health(dialCtx)
+	if err != nil {
+		return fmt.Errorf("error connecting to L2 node %s, err: %v", m.cfg.L2NodeURL, err)
+	}
+
+	log.Debugf("reorg-detector: started")
+	ready()
+
+	for {
+		select {
+		case <-ctx.Done():
+			log.Debugf("reorg-detector: stopped")
+			return nil
+		case <-time.After(m.cfg.ReorgDetection.CheckInterval.Duration):
+		}
+
+		if !m.leader.IsLeader() {
+			continue
+		}
+
+		if err := m.checkL2Reorgs(ctx, rpcClient); err != nil && ctx.Err() == nil {
+			return err
+		}
+	}
+}
+
+// checkL2Reorgs compares the block hashes of the receipts of the txs confirmed in the last ReorgDetection.Depth blocks
+// with the canonical block hashes, and moves the txs of the reorged blocks back to be monitored or resent. It returns an
+// error if the calls to the L2 node fail, the errors of the pool database are logged and retried in the next check
+func (m *Monitor) checkL2Reorgs(ctx context.Context, rpcClient rpcclient.L2NodeClient) error {
+	var lastBlock uint64
+	err := m.callL2Node(ctx, func(ctx context.Context) (err error) {
+		lastBlock, err = rpcClient.BlockNumber(ctx)
+		return err
+	})
+	if err != nil {
+		return fmt.Errorf("error getting block number from L2 node %s, err: %v", m.cfg.L2NodeURL, err)
+	}
+
+	fromBlock := uint64(0)
+	if lastBlock > m.cfg.ReorgDetection.Depth {
+		fromBlock = lastBlock - m.cfg.ReorgDetection.Depth
+	}
+
+	blocks, err := m.poolDB.GetL2BlocksToCheckReorg(ctx, fromBlock)
+	if err != nil {
+		log.Errorf("error getting L2 blocks to check reorgs from the pool db, error: %v", err)
+		return nil
+	}
+
+	for _, block := range blocks {
+		var canonicalHash common.Hash
+		err := m.callL2Node(ctx, func(ctx context.Context) (err error) {
+			canonicalHash, err = rpcClient.BlockHash(ctx, block.Number)
+			return err
+		})
+		if errors.Is(err, ethereum.NotFound) {
+			// The block doesn't exist anymore, the L2 node has been unwound
+			m.reorgL2Transactions(ctx, block, "")
+			continue
+		} else if err != nil {
+			return fmt.Errorf("error getting hash of block %d from L2 node %s, err: %v", block.Number, m.cfg.L2NodeURL, err)
+		}
+
+		if canonicalHash != common.HexToHash(block.Hash) {
+			m.reorgL2Transactions(ctx, block, canonicalHash.Hex())
+		}
+	}
+
+	return nil
+}
+
+// reorgL2Transactions moves the txs of the reorged block to resend status if ReorgDetection.Resend is enabled, or to
+// sent status monitoring them again
+func (m *Monitor) reorgL2Transactions(ctx context.Context, block *types.L2Block, canonicalHash string) {
+	newStatus := types.TxStatusSent
+	if m.cfg.ReorgDetection.Resend {
+		newStatus = types.TxStatusResend
+	}
+
+	l2Txs, err := m.poolDB.ReorgL2Transactions(ctx, block, canonicalHash, newStatus)
+	if err != nil {
+		log.Errorf("error updating txs of reorged block %d (%s) in the pool db, error: %v", block.Number, block.Hash, err)
+		return
+	}
+
+	log.Warnf("L2 reorg detected, block %d hash %s is not canonical (canonical hash: %s), %d txs moved to %s status",
+		block.Number, block.Hash, canonicalHash, len(l2Txs), newStatus)
+
+	if newStatus == types.TxStatusSent {
+		for _, l2Tx := range l2Txs {
+			m.AddL2Transaction(l2Tx)
+		}
+	}
+}
@@ -0,0 +1,134 @@
+package monitor
+
+import (
+	"container/heap"
+	"sync"
+
+	"github.com/0xPolygonHermez/zkevm-pool-manager/log"
+)
+
+// requestScheduler holds the monitor requests waiting for their next retry, indexed by tx id and ordered by nextRetry
+// in a min-heap, so adding, deleting and taking the next request are O(log n). The requests with the same nextRetry
+// are ordered by the time they were added. The nextRetry of a request must not be changed while it's scheduled
+type requestScheduler struct {
+	requests map[uint64]*monitorRequest
+	heap     requestHeap
+	// seq is the sequence number of the last added request, used to order the requests with the same nextRetry
+	seq uint64
+	// wakeup is signaled when an added request becomes the next one to retry, so the wait for the previous one can
+	// be interrupted
+	wakeup chan struct{}
+	mutex  sync.Mutex
+}
+
+// newRequestScheduler creates an empty request scheduler
+func newRequestScheduler() *requestScheduler {
+	return &requestScheduler{
+		requests: make(map[uint64]*monitorRequest),
+		wakeup:   make(chan struct{}, 1),
+	}
+}
+
+// add schedules the request for its nextRetry time. Returns false if a request for the same tx is already scheduled
+func (s *requestScheduler) add(request *monitorRequest) bool {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if _, found := s.requests[request.l2Tx.Id]; found {
+		return false
+	}
+
+	s.seq++
+	request.seq = s.seq
+	s.requests[request.l2Tx.Id] = request
+	heap.Push(&s.heap, request)
+	log.Debugf("added monitor request for tx %s with nextRetry time %v to the retry scheduler, total %d", request.l2Tx.Tag(), request.nextRetry, len(s.heap))
+
+	if request.heapIndex == 0 {
+		select {
+		case s.wakeup <- struct{}{}:
+		default:
+		}
+	}
+	return true
+}
+
+// delete removes the request from the scheduler. Returns false if the request is not scheduled, as it has been taken
+// by someone else
+func (s *requestScheduler) delete(request *monitorRequest) bool {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if scheduled, found := s.requests[request.l2Tx.Id]; !found || scheduled != request {
+		return false
+	}
+
+	delete(s.requests, request.l2Tx.Id)
+	heap.Remove(&s.heap, request.heapIndex)
+	return true
+}
+
+// peek returns the next request to retry, without removing it from the scheduler
+func (s *requestScheduler) peek() (*monitorRequest, bool) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if len(s.heap) == 0 {
+		return nil, false
+	}
+	return s.heap[0], true
+}
+
+// len returns the number of scheduled requests
+func (s *requestScheduler) len() int {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	return len(s.heap)
+}
+
+// scheduled returns the scheduled requests, in no particular order
+func (s *requestScheduler) scheduled() []*monitorRequest {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	requests := make([]*monitorRequest, len(s.heap))
+	copy(requests, s.heap)
+	return requests
+}
+
+// requestHeap is a min-heap of monitor requests ordered by nextRetry and sequence number. It implements heap.Interface
+type requestHeap []*monitorRequest
+
+func (h requestHeap) Len() int {
+	return len(h)
+}
+
+func (h requestHeap) Less(i, j int) bool {
+	if h[i].nextRetry.Equal(h[j].nextRetry) {
+		return h[i].seq < h[j].seq
+	}
+	return h[i].nextRetry.Before(h[j].nextRetry)
+}
+
+func (h requestHeap) Swap(i, j int) {
+	h[i], h[j] = h[j], h[i]
+	h[i].heapIndex = i
+	h[j].heapIndex = j
+}
+
+func (h *requestHeap) Push(x any) {
+	request := x.(*monitorRequest)
+	request.heapIndex = len(*h)
+	*h = append(*h, request)
+}
+
+func (h *requestHeap) Pop() any {
+	old := *h
+	n := len(old)
+	request := old[n-1]
+	old[n-1] = nil
+	request.heapIndex = -1
+	*h = old[:n-1]
+	return request
+}
@@ -0,0 +1,258 @@
+package monitor
+
+import (
+	"context"
+	"fmt"
+	"math/rand"
+	"sort"
+	"testing"
+	"testing/quick"
+	"time"
+
+	"github.com/0xPolygonHermez/zkevm-pool-manager/config/types"
+	"github.com/0xPolygonHermez/zkevm-pool-manager/log"
+	poolTypes "github.com/0xPolygonHermez/zkevm-pool-manager/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestRequest(id uint64, nextRetry time.Time) *monitorRequest {
+	return &monitorRequest{
+		l2Tx:      poolTypes.L2Transaction{Id: id, Hash: fmt.Sprintf("0x%02x", id)},
+		nextRetry: nextRetry,
+	}
+}
+
+// takeAll takes the scheduled requests in retry order
+func takeAll(s *requestScheduler) []uint64 {
+	var ids []uint64
+	for {
+		request, found := s.peek()
+		if !found {
+			return ids
+		}
+		s.delete(request)
+		ids = append(ids, request.l2Tx.Id)
+	}
+}
+
+func TestRequestScheduler(t *testing.T) {
+	s := newRequestScheduler()
+
+	now := time.Now()
+	past := now.Add(-time.Minute * 5)
+	future := now.Add(time.Minute * 5)
+
+	requests := map[uint64]*monitorRequest{
+		1: newTestRequest(1, now),
+		2: newTestRequest(2, now),
+		3: newTestRequest(3, past),
+		4: newTestRequest(4, past),
+		5: newTestRequest(5, future),
+	}
+	for id := uint64(1); id <= 5; id++ {
+		require.True(t, s.add(requests[id]))
+	}
+	assert.False(t, s.add(newTestRequest(1, past)), "the tx is already scheduled")
+	assert.Equal(t, 5, s.len())
+
+	// Only the scheduled request of the tx can be deleted
+	assert.False(t, s.delete(newTestRequest(1, now)))
+	for _, id := range []uint64{1, 4, 5} {
+		assert.True(t, s.delete(requests[id]))
+		assert.False(t, s.delete(requests[id]))
+	}
+
+	assert.Equal(t, []uint64{3, 2}, takeAll(s))
+	assert.Equal(t, 0, s.len())
+}
+
+func TestRequestSchedulerWakeup(t *testing.T) {
+	s := newRequestScheduler()
+	now := time.Now()
+
+	s.add(newTestRequest(1, now))
+	<-s.wakeup
+
+	// A later request doesn't change the next request to retry
+	s.add(newTestRequest(2, now.Add(time.Minute)))
+	select {
+	case <-s.wakeup:
+		t.Fatal("unexpected wakeup adding a later request")
+	default:
+	}
+
+	s.add(newTestRequest(3, now.Add(-time.Minute)))
+	select {
+	case <-s.wakeup:
+	default:
+		t.Fatal("expected wakeup adding an earlier request")
+	}
+}
+
+func TestCheckMonitorRequestRetriesWakeup(t *testing.T) {
+	m := NewMonitor(Config{TxLifeTimeMax: types.NewDuration(time.Hour), QueueSize: 1}, &fakePoolDB{}, &fakeLeader{})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go m.checkMonitorRequestRetries(ctx)
+
+	now := time.Now()
+	m.retryScheduler.add(&monitorRequest{l2Tx: poolTypes.L2Transaction{Id: 1, Hash: "0x01", ReceivedAt: now}, nextRetry: now.Add(time.Hour)})
+	time.Sleep(10 * time.Millisecond)
+	m.retryScheduler.add(&monitorRequest{l2Tx: poolTypes.L2Transaction{Id: 2, Hash: "0x02", ReceivedAt: now}, nextRetry: now})
+
+	select {
+	case request := <-m.requestChan:
+		assert.Equal(t, uint64(2), request.l2Tx.Id)
+	case <-time.After(time.Second):
+		t.Fatal("the earlier request was not retried while waiting for the later one")
+	}
+	assert.Equal(t, 1, m.retryScheduler.len())
+}
+
+// schedulerOp is an operation on the scheduler generated by testing/quick
+type schedulerOp struct {
+	// Kind selects the operation: add, delete or take the next request
+	Kind uint8
+	// Id is the tx id of the added or deleted request
+	Id uint8
+	// Offset is the retry time of the added request in milliseconds from the base time. Few distinct values are
+	// used so many requests have the same retry time
+	Offset uint8
+}
+
+// sortedRequestList is the reference model of the scheduler: the sorted slice of the previous monitorRequestList, that
+// kept the requests ordered by nextRetry and in insertion order for the same nextRetry
+type sortedRequestList struct {
+	sorted []*monitorRequest
+}
+
+func (l *sortedRequestList) add(request *monitorRequest) bool {
+	for _, r := range l.sorted {
+		if r.l2Tx.Id == request.l2Tx.Id {
+			return false
+		}
+	}
+	i := sort.Search(len(l.sorted), func(i int) bool {
+		return l.sorted[i].nextRetry.UnixMilli() > request.nextRetry.UnixMilli()
+	})
+	l.sorted = append(l.sorted, nil)
+	copy(l.sorted[i+1:], l.sorted[i:])
+	l.sorted[i] = request
+	return true
+}
+
+func (l *sortedRequestList) delete(id uint64) bool {
+	for i, r := range l.sorted {
+		if r.l2Tx.Id == id {
+			l.sorted = append(l.sorted[:i], l.sorted[i+1:]...)
+			return true
+		}
+	}
+	return false
+}
+
+func TestRequestSchedulerProperties(t *testing.T) {
+	base := time.UnixMilli(time.Now().UnixMilli())
+
+	property := func(ops []schedulerOp) bool {
+		s := newRequestScheduler()
+		model := &sortedRequestList{}
+		scheduled := make(map[uint64]*monitorRequest)
+
+		for _, op := range ops {
+			id := uint64(op.Id % 32)
+			switch op.Kind % 3 {
+			case 0:
+				request := newTestRequest(id, base.Add(time.Duration(op.Offset%8)*time.Millisecond))
+				added := s.add(request)
+				if added != model.add(request) {
+					return false
+				}
+				if added {
+					scheduled[id] = request
+				}
+			case 1:
+				request, found := scheduled[id]
+				if !found {
+					request = newTestRequest(id, base)
+				}
+				if s.delete(request) != model.delete(id) {
+					return false
+				}
+				delete(scheduled, id)
+			case 2:
+				request, found := s.peek()
+				if found != (len(model.sorted) > 0) {
+					return false
+				}
+				if found {
+					if request != model.sorted[0] || !s.delete(request) || !model.delete(request.l2Tx.Id) {
+						return false
+					}
+					delete(scheduled, request.l2Tx.Id)
+				}
+			}
+
+			if s.len() != len(model.sorted) {
+				return false
+			}
+		}
+
+		// The remaining requests are taken in the same order
+		for _, request := range model.sorted {
+			next, found := s.peek()
+			if !found || next != request || !s.delete(next) {
+				return false
+			}
+		}
+		return s.len() == 0
+	}
+
+	require.NoError(t, quick.Check(property, &quick.Config{MaxCount: 1000}))
+}
+
+func benchmarkSizes(b *testing.B, bench func(b *testing.B, s *requestScheduler, requests []*monitorRequest)) {
+	// The debug logs of the scheduler would be measured instead of the scheduler
+	log.Init(log.Config{Environment: "production", Level: "error", Outputs: []string{"stderr"}})
+
+	for _, size := range []int{100_000, 1_000_000} {
+		b.Run(fmt.Sprintf("txs=%d", size), func(b *testing.B) {
+			now := time.Now()
+			s := newRequestScheduler()
+			requests := make([]*monitorRequest, size)
+			for i := range requests {
+				requests[i] = newTestRequest(uint64(i), now.Add(time.Duration(rand.Int63n(int64(time.Hour)))))
+				s.add(requests[i])
+			}
+			b.ResetTimer()
+			bench(b, s, requests)
+		})
+	}
+}
+
+// BenchmarkRequestSchedulerRetry takes the next request and schedules it again, as done for the txs whose receipt is
+// still not available
+func BenchmarkRequestSchedulerRetry(b *testing.B) {
+	benchmarkSizes(b, func(b *testing.B, s *requestScheduler, requests []*monitorRequest) {
+		for i := 0; i < b.N; i++ {
+			request, _ := s.peek()
+			s.delete(request)
+			request.nextRetry = request.nextRetry.Add(time.Hour)
+			s.add(request)
+		}
+	})
+}
+
+// BenchmarkRequestSchedulerDelete deletes random requests and schedules them again, as done for the txs found in the
+// new L2 blocks
+func BenchmarkRequestSchedulerDelete(b *testing.B) {
+	benchmarkSizes(b, func(b *testing.B, s *requestScheduler, requests []*monitorRequest) {
+		for i := 0; i < b.N; i++ {
+			request := requests[rand.Intn(len(requests))]
+			s.delete(request)
+			s.add(request)
+		}
+	})
+}
@@ -0,0 +1,103 @@
+package monitor
+
+import (
+	"context"
+	"math/rand"
+	"time"
+
+	"github.com/0xPolygonHermez/zkevm-pool-manager/log"
+	"github.com/0xPolygonHermez/zkevm-pool-manager/types"
+)
+
+// restoreL2Transaction monitors a sent tx loaded from the pool database. If RetrySchedule is enabled the receipt is
+// checked at the persisted time of the next check, and if it's overdue (or it was never checked) the check is delayed
+// a random time up to the RestoreJitter, so the loaded txs are not checked at once
+func (m *Monitor) restoreL2Transaction(l2Tx *types.L2Transaction) {
+	if !m.cfg.RetrySchedule.Enabled {
+		m.AddL2Transaction(l2Tx)
+		return
+	}
+
+	request := &monitorRequest{
+		l2Tx:      *l2Tx,
+		nextRetry: l2Tx.NextCheckAt,
+	}
+
+	if !m.trackL2Transaction(request) {
+		log.Debugf("tx %s is already being monitored or the monitor is stopped", l2Tx.Tag())
+		return
+	}
+
+	now := time.Now()
+	if request.nextRetry.Before(now) {
+		request.nextRetry = now
+		if jitter := m.cfg.RetrySchedule.RestoreJitter.Duration; jitter > 0 {
+			request.nextRetry = now.Add(time.Duration(rand.Int63n(int64(jitter))))
+		}
+	}
+	m.retryScheduler.add(request)
+}
+
+// recordRequestSchedule records the schedule of the next check of the receipt of the request, to persist it in the
+// next flush to the pool database, if RetrySchedule is enabled
+func (m *Monitor) recordRequestSchedule(request *monitorRequest) {
+	if !m.cfg.RetrySchedule.Enabled {
+		return
+	}
+
+	m.pendingChecksMutex.Lock()
+	defer m.pendingChecksMutex.Unlock()
+
+	m.pendingChecks[request.l2Tx.Id] = types.L2TransactionCheck{
+		TxId:        request.l2Tx.Id,
+		NextCheckAt: request.nextRetry,
+		CheckCount:  request.l2Tx.CheckCount,
+	}
+}
+
+// persistRetrySchedules periodically writes the recorded schedules of the receipt checks to the pool database
+func (m *Monitor) persistRetrySchedules(ctx context.Context) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(m.cfg.RetrySchedule.FlushInterval.Duration):
+		}
+
+		m.flushRetrySchedules(ctx)
+	}
+}
+
+// flushRetrySchedules writes the recorded schedules of the receipt checks to the pool database. If the write fails the
+// schedules are kept to be written in the next flush, unless they have been recorded again meanwhile
+func (m *Monitor) flushRetrySchedules(ctx context.Context) {
+	m.pendingChecksMutex.Lock()
+	pending := m.pendingChecks
+	m.pendingChecks = make(map[uint64]types.L2TransactionCheck)
+	m.pendingChecksMutex.Unlock()
+
+	if len(pending) == 0 {
+		return
+	}
+
+	checks := make([]types.L2TransactionCheck, 0, len(pending))
+	for _, check := range pending {
+		checks = append(checks, check)
+	}
+
+	updated, err := m.poolDB.UpdateL2TransactionsNextCheck(ctx, checks)
+	if err != nil {
+		log.Errorf("error persisting the schedule of %d receipt checks in the pool db, error: %v", len(checks), err)
+
+		m.pendingChecksMutex.Lock()
+		defer m.pendingChecksMutex.Unlock()
+		for id, check := range pending {
+			if _, found := m.pendingChecks[id]; !found {
+				m.pendingChecks[id] = check
+			}
+		}
+		return
+	}
+
+	log.Debugf("persisted the schedule of %d receipt checks, updated txs: %d", len(checks), updated)
+}
@@ -0,0 +1,53 @@
+package rpcclient
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/0xPolygonHermez/zkevm-pool-manager/config/types"
+)
+
+const redacted = "<redacted>"
+
+// Config for the authentication of the RPC clients used to connect to the sequencer and the L2 node
+type Config struct {
+	// Headers are static HTTP headers sent in every request
+	Headers map[string]string `mapstructure:"Headers"`
+
+	// BearerToken is a static token sent in the Authorization header
+	BearerToken string `mapstructure:"BearerToken"`
+
+	// BearerTokenFile is the path of a file with the token sent in the Authorization header. The file is read again
+	// every TokenRefreshInterval, so the token can be rotated without restarting the pool-manager
+	BearerTokenFile string `mapstructure:"BearerTokenFile"`
+
+	// JWTSecretFile is the path of a file with the hex encoded 32 bytes secret used to sign HS256 JWT tokens sent in the
+	// Authorization header (same as the engine API authentication)
+	JWTSecretFile string `mapstructure:"JWTSecretFile"`
+
+	// TokenRefreshInterval is the time a token is used before it's refreshed
+	TokenRefreshInterval types.Duration `mapstructure:"TokenRefreshInterval"`
+}
+
+// String returns the configuration without the secrets, so it can be safely logged
+func (c Config) String() string {
+	headers := make([]string, 0, len(c.Headers))
+	for name := range c.Headers {
+		headers = append(headers, fmt.Sprintf("%s: %s", name, redacted))
+	}
+	sort.Strings(headers)
+
+	bearerToken := ""
+	if c.BearerToken != "" {
+		bearerToken = redacted
+	}
+
+	return fmt.Sprintf("{Headers: [%s], BearerToken: %s, BearerTokenFile: %s, JWTSecretFile: %s, TokenRefreshInterval: %v}",
+		strings.Join(headers, ", "), bearerToken, c.BearerTokenFile, c.JWTSecretFile, c.TokenRefreshInterval.Duration)
+}
+
+// GoString returns the configuration without the secrets, so it can be safely logged using %#v
+func (c Config) GoString() string {
+	return c.String()
+}
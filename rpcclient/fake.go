@@ -0,0 +1,409 @@
+package rpcclient
+
+import (
+	"context"
+	"math/big"
+	"sync"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	ethTypes "github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/event"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+// FakeSequencerClient is an in-memory SequencerClient, used to test the sender without a sequencer
+type FakeSequencerClient struct {
+	chainID    uint64
+	healthErr  error
+	batchErr   error
+	sendErrors map[string]error
+	sent       []string
+	mutex      sync.Mutex
+}
+
+// NewFakeSequencerClient creates a FakeSequencerClient for the chain id
+func NewFakeSequencerClient(chainID uint64) *FakeSequencerClient {
+	return &FakeSequencerClient{
+		chainID:    chainID,
+		sendErrors: make(map[string]error),
+	}
+}
+
+// SetSendError sets the error returned when the encoded tx is sent. A nil error removes it
+func (c *FakeSequencerClient) SetSendError(encoded string, err error) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	if err == nil {
+		delete(c.sendErrors, encoded)
+		return
+	}
+	c.sendErrors[encoded] = err
+}
+
+// SetBatchError sets the error returned by SendRawTransactions for the whole batch, to simulate a transport error. A nil
+// error removes it
+func (c *FakeSequencerClient) SetBatchError(err error) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	c.batchErr = err
+}
+
+// SetHealthError sets the error returned by Health and ChainID, to simulate the sequencer is down
+func (c *FakeSequencerClient) SetHealthError(err error) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	c.healthErr = err
+}
+
+// Sent returns the encoded txs sent successfully, in the order they were sent
+func (c *FakeSequencerClient) Sent() []string {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	return append([]string(nil), c.sent...)
+}
+
+func (c *FakeSequencerClient) SendRawTransaction(ctx context.Context, encoded string) error {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	if err := c.sendErrors[encoded]; err != nil {
+		return err
+	}
+	c.sent = append(c.sent, encoded)
+	return nil
+}
+
+func (c *FakeSequencerClient) SendRawTransactions(ctx context.Context, encoded []string) ([]error, error) {
+	c.mutex.Lock()
+	batchErr := c.batchErr
+	c.mutex.Unlock()
+	if batchErr != nil {
+		return nil, batchErr
+	}
+
+	errs := make([]error, len(encoded))
+	for i, tx := range encoded {
+		errs[i] = c.SendRawTransaction(ctx, tx)
+	}
+	return errs, nil
+}
+
+func (c *FakeSequencerClient) ChainID(ctx context.Context) (uint64, error) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	if c.healthErr != nil {
+		return 0, c.healthErr
+	}
+	return c.chainID, nil
+}
+
+func (c *FakeSequencerClient) Health(ctx context.Context) error {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	return c.healthErr
+}
+
+func (c *FakeSequencerClient) Close() {}
+
+// FakeL2NodeClient is an in-memory L2NodeClient, used to test the monitor without a L2 node
+type FakeL2NodeClient struct {
+	chainID   uint64
+	healthErr error
+	receipts  map[common.Hash]*ethTypes.Receipt
+	// blocks holds the tx hashes of the L2 blocks, the block n is at index n-1
+	blocks       [][]common.Hash
+	blockHashes  map[uint64]common.Hash
+	headsFeed    event.Feed
+	noSubscribe  bool
+	receiptCalls int
+	batchCalls   int
+	// batches holds the L2 batch number of each L2 block
+	batches       map[uint64]uint64
+	virtualBatch  uint64
+	verifiedBatch uint64
+	// pending holds the txs known by the L2 node that are not included in a block yet
+	pending map[common.Hash]struct{}
+	// nonces holds the nonce of the accounts
+	nonces map[common.Address]uint64
+	mutex  sync.Mutex
+}
+
+// NewFakeL2NodeClient creates a FakeL2NodeClient for the chain id
+func NewFakeL2NodeClient(chainID uint64) *FakeL2NodeClient {
+	return &FakeL2NodeClient{
+		chainID:     chainID,
+		receipts:    make(map[common.Hash]*ethTypes.Receipt),
+		batches:     make(map[uint64]uint64),
+		blockHashes: make(map[uint64]common.Hash),
+		pending:     make(map[common.Hash]struct{}),
+		nonces:      make(map[common.Address]uint64),
+	}
+}
+
+// SetBlockBatch sets the L2 batch number that includes the L2 block
+func (c *FakeL2NodeClient) SetBlockBatch(blockNumber uint64, batchNumber uint64) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	c.batches[blockNumber] = batchNumber
+}
+
+// SetBatchState sets the numbers of the last virtualized and verified L2 batches
+func (c *FakeL2NodeClient) SetBatchState(virtualBatch uint64, verifiedBatch uint64) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	c.virtualBatch = virtualBatch
+	c.verifiedBatch = verifiedBatch
+}
+
+// SetReceipt sets the receipt returned for the tx. A nil receipt removes it
+func (c *FakeL2NodeClient) SetReceipt(hash common.Hash, receipt *ethTypes.Receipt) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	if receipt == nil {
+		delete(c.receipts, hash)
+		return
+	}
+	c.receipts[hash] = receipt
+}
+
+// SetPendingTransaction sets if the tx is known by the L2 node, waiting to be included in a block
+func (c *FakeL2NodeClient) SetPendingTransaction(hash common.Hash, pending bool) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	if !pending {
+		delete(c.pending, hash)
+		return
+	}
+	c.pending[hash] = struct{}{}
+}
+
+// SetNonce sets the nonce of the account
+func (c *FakeL2NodeClient) SetNonce(account common.Address, nonce uint64) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	c.nonces[account] = nonce
+}
+
+// SetHealthError sets the error returned by all the calls, to simulate the L2 node is down
+func (c *FakeL2NodeClient) SetHealthError(err error) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	c.healthErr = err
+}
+
+// AddBlock adds a L2 block with the txs and notifies its header to the subscribers. Returns the block number
+func (c *FakeL2NodeClient) AddBlock(hashes ...common.Hash) uint64 {
+	c.mutex.Lock()
+	c.blocks = append(c.blocks, hashes)
+	number := uint64(len(c.blocks))
+	c.mutex.Unlock()
+
+	c.headsFeed.Send(&ethTypes.Header{Number: new(big.Int).SetUint64(number)})
+	return number
+}
+
+// SetBlockHash sets the hash of the L2 block, to simulate the block has been reorged. By default the hash of a block is
+// its number
+func (c *FakeL2NodeClient) SetBlockHash(number uint64, hash common.Hash) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	c.blockHashes[number] = hash
+}
+
+// DisableSubscriptions makes SubscribeNewHeads fail as the HTTP transport does, to force the polling of blocks
+func (c *FakeL2NodeClient) DisableSubscriptions() {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	c.noSubscribe = true
+}
+
+// ReceiptCalls returns the number of calls to TransactionReceipt
+func (c *FakeL2NodeClient) ReceiptCalls() int {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	return c.receiptCalls
+}
+
+// ReceiptBatchCalls returns the number of calls to TransactionReceipts
+func (c *FakeL2NodeClient) ReceiptBatchCalls() int {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	return c.batchCalls
+}
+
+func (c *FakeL2NodeClient) TransactionReceipt(ctx context.Context, hash common.Hash) (*ethTypes.Receipt, error) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	c.receiptCalls++
+	return c.receipt(hash)
+}
+
+func (c *FakeL2NodeClient) TransactionReceipts(ctx context.Context, hashes []common.Hash) ([]*ethTypes.Receipt, []error, error) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	c.batchCalls++
+	if c.healthErr != nil {
+		return nil, nil, c.healthErr
+	}
+
+	receipts := make([]*ethTypes.Receipt, len(hashes))
+	errs := make([]error, len(hashes))
+	for i, hash := range hashes {
+		receipts[i], errs[i] = c.receipt(hash)
+	}
+	return receipts, errs, nil
+}
+
+// receipt returns the receipt of the tx. It must be called with the mutex locked
+func (c *FakeL2NodeClient) receipt(hash common.Hash) (*ethTypes.Receipt, error) {
+	if c.healthErr != nil {
+		return nil, c.healthErr
+	}
+	receipt, found := c.receipts[hash]
+	if !found {
+		return nil, ethereum.NotFound
+	}
+	return receipt, nil
+}
+
+// TransactionByHash returns the pending txs and the txs with receipt as known by the L2 node
+func (c *FakeL2NodeClient) TransactionByHash(ctx context.Context, hash common.Hash) (bool, error) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	if c.healthErr != nil {
+		return false, c.healthErr
+	}
+	if _, found := c.pending[hash]; found {
+		return true, nil
+	}
+	if _, found := c.receipts[hash]; found {
+		return false, nil
+	}
+	return false, ethereum.NotFound
+}
+
+func (c *FakeL2NodeClient) NonceAt(ctx context.Context, account common.Address) (uint64, error) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	if c.healthErr != nil {
+		return 0, c.healthErr
+	}
+	return c.nonces[account], nil
+}
+
+func (c *FakeL2NodeClient) BlockNumber(ctx context.Context) (uint64, error) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	if c.healthErr != nil {
+		return 0, c.healthErr
+	}
+	return uint64(len(c.blocks)), nil
+}
+
+func (c *FakeL2NodeClient) BlockTransactionHashes(ctx context.Context, number uint64) ([]common.Hash, error) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	if c.healthErr != nil {
+		return nil, c.healthErr
+	}
+	if number == 0 || number > uint64(len(c.blocks)) {
+		return nil, ethereum.NotFound
+	}
+	return c.blocks[number-1], nil
+}
+
+func (c *FakeL2NodeClient) BlockHash(ctx context.Context, number uint64) (common.Hash, error) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	if c.healthErr != nil {
+		return common.Hash{}, c.healthErr
+	}
+	if number == 0 || number > uint64(len(c.blocks)) {
+		return common.Hash{}, ethereum.NotFound
+	}
+	if hash, found := c.blockHashes[number]; found {
+		return hash, nil
+	}
+	return common.BigToHash(new(big.Int).SetUint64(number)), nil
+}
+
+func (c *FakeL2NodeClient) SubscribeNewHeads(ctx context.Context, ch chan<- *ethTypes.Header) (ethereum.Subscription, error) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	if c.noSubscribe {
+		return nil, rpc.ErrNotificationsUnsupported
+	}
+	return c.headsFeed.Subscribe(ch), nil
+}
+
+func (c *FakeL2NodeClient) BatchNumberByBlockNumber(ctx context.Context, number uint64) (uint64, error) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	if c.healthErr != nil {
+		return 0, c.healthErr
+	}
+	batchNumber, found := c.batches[number]
+	if !found {
+		return 0, ethereum.NotFound
+	}
+	return batchNumber, nil
+}
+
+func (c *FakeL2NodeClient) VirtualBatchNumber(ctx context.Context) (uint64, error) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	return c.virtualBatch, c.healthErr
+}
+
+func (c *FakeL2NodeClient) VerifiedBatchNumber(ctx context.Context) (uint64, error) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	return c.verifiedBatch, c.healthErr
+}
+
+func (c *FakeL2NodeClient) ChainID(ctx context.Context) (uint64, error) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	if c.healthErr != nil {
+		return 0, c.healthErr
+	}
+	return c.chainID, nil
+}
+
+func (c *FakeL2NodeClient) Health(ctx context.Context) error {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	return c.healthErr
+}
+
+func (c *FakeL2NodeClient) Close() {}
@@ -0,0 +1,202 @@
+package rpcclient
+
+import (
+	"context"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	ethTypes "github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+// L2NodeClient is the client used to get the receipts of the txs from the L2 node
+type L2NodeClient interface {
+	// TransactionReceipt returns the receipt of the tx. It returns ethereum.NotFound if the receipt is not available
+	TransactionReceipt(ctx context.Context, hash common.Hash) (*ethTypes.Receipt, error)
+	// TransactionReceipts returns the receipts of the txs in a single request. It returns the receipt and the error of
+	// each tx (ethereum.NotFound if the receipt is not available), or an error if the request failed
+	TransactionReceipts(ctx context.Context, hashes []common.Hash) ([]*ethTypes.Receipt, []error, error)
+	// TransactionByHash returns if the tx is pending, waiting to be included in a block. It returns ethereum.NotFound if
+	// the tx is not known by the L2 node
+	TransactionByHash(ctx context.Context, hash common.Hash) (bool, error)
+	// NonceAt returns the nonce of the account in the last L2 block
+	NonceAt(ctx context.Context, account common.Address) (uint64, error)
+	// BlockNumber returns the number of the last L2 block
+	BlockNumber(ctx context.Context) (uint64, error)
+	// BlockTransactionHashes returns the hashes of the txs of the L2 block. It returns ethereum.NotFound if the block
+	// doesn't exist yet
+	BlockTransactionHashes(ctx context.Context, number uint64) ([]common.Hash, error)
+	// BlockHash returns the hash of the L2 block. It returns ethereum.NotFound if the block doesn't exist
+	BlockHash(ctx context.Context, number uint64) (common.Hash, error)
+	// SubscribeNewHeads subscribes to the headers of the new L2 blocks. It returns rpc.ErrNotificationsUnsupported if
+	// the transport doesn't support subscriptions (HTTP)
+	SubscribeNewHeads(ctx context.Context, ch chan<- *ethTypes.Header) (ethereum.Subscription, error)
+	// BatchNumberByBlockNumber returns the number of the L2 batch that includes the L2 block. Returns
+	// ethereum.NotFound if the block is unknown or not included in a batch yet
+	BatchNumberByBlockNumber(ctx context.Context, number uint64) (uint64, error)
+	// VirtualBatchNumber returns the number of the last L2 batch virtualized (sequenced) on L1
+	VirtualBatchNumber(ctx context.Context) (uint64, error)
+	// VerifiedBatchNumber returns the number of the last L2 batch verified on L1
+	VerifiedBatchNumber(ctx context.Context) (uint64, error)
+	// ChainID returns the chain id of the L2 node
+	ChainID(ctx context.Context) (uint64, error)
+	// Health returns an error if the L2 node is not able to process requests
+	Health(ctx context.Context) error
+	// Close closes the connection to the L2 node
+	Close()
+}
+
+// jsonRPCL2NodeClient is a L2NodeClient that uses JSON-RPC over HTTP, WebSocket or IPC
+type jsonRPCL2NodeClient struct {
+	client *ethclient.Client
+}
+
+// DialL2Node connects to the L2 node at the url using the transport of the url scheme
+func DialL2Node(ctx context.Context, url string, cfg Config) (L2NodeClient, error) {
+	client, err := Dial(ctx, url, cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	return &jsonRPCL2NodeClient{client: client}, nil
+}
+
+func (c *jsonRPCL2NodeClient) TransactionReceipt(ctx context.Context, hash common.Hash) (*ethTypes.Receipt, error) {
+	return c.client.TransactionReceipt(ctx, hash)
+}
+
+func (c *jsonRPCL2NodeClient) TransactionReceipts(ctx context.Context, hashes []common.Hash) ([]*ethTypes.Receipt, []error, error) {
+	receipts := make([]*ethTypes.Receipt, len(hashes))
+	elems := make([]rpc.BatchElem, len(hashes))
+	for i, hash := range hashes {
+		elems[i] = rpc.BatchElem{
+			Method: "eth_getTransactionReceipt",
+			Args:   []interface{}{hash},
+			Result: &receipts[i],
+		}
+	}
+
+	err := c.client.Client().BatchCallContext(ctx, elems)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	errs := make([]error, len(elems))
+	for i := range elems {
+		errs[i] = elems[i].Error
+		if errs[i] == nil && receipts[i] == nil {
+			errs[i] = ethereum.NotFound
+		}
+	}
+
+	return receipts, errs, nil
+}
+
+// rpcTransaction is a tx returned by eth_getTransactionByHash, only the block number is needed
+type rpcTransaction struct {
+	BlockNumber *hexutil.Big `json:"blockNumber"`
+}
+
+func (c *jsonRPCL2NodeClient) TransactionByHash(ctx context.Context, hash common.Hash) (bool, error) {
+	var tx *rpcTransaction
+	err := c.client.Client().CallContext(ctx, &tx, "eth_getTransactionByHash", hash)
+	if err != nil {
+		return false, err
+	}
+	if tx == nil {
+		return false, ethereum.NotFound
+	}
+
+	return tx.BlockNumber == nil, nil
+}
+
+func (c *jsonRPCL2NodeClient) NonceAt(ctx context.Context, account common.Address) (uint64, error) {
+	return c.client.NonceAt(ctx, account, nil)
+}
+
+func (c *jsonRPCL2NodeClient) BlockNumber(ctx context.Context) (uint64, error) {
+	return c.client.BlockNumber(ctx)
+}
+
+// rpcBlock is a L2 block returned by eth_getBlockByNumber without the full txs
+type rpcBlock struct {
+	Hash         common.Hash   `json:"hash"`
+	Transactions []common.Hash `json:"transactions"`
+}
+
+// getBlock returns the L2 block without the full txs, as only the hashes are needed. The hash is read from the response
+// instead of computing it from the header, as the L2 node may compute it differently
+func (c *jsonRPCL2NodeClient) getBlock(ctx context.Context, number uint64) (*rpcBlock, error) {
+	var block *rpcBlock
+	err := c.client.Client().CallContext(ctx, &block, "eth_getBlockByNumber", hexutil.EncodeUint64(number), false)
+	if err != nil {
+		return nil, err
+	}
+	if block == nil {
+		return nil, ethereum.NotFound
+	}
+
+	return block, nil
+}
+
+func (c *jsonRPCL2NodeClient) BlockTransactionHashes(ctx context.Context, number uint64) ([]common.Hash, error) {
+	block, err := c.getBlock(ctx, number)
+	if err != nil {
+		return nil, err
+	}
+
+	return block.Transactions, nil
+}
+
+func (c *jsonRPCL2NodeClient) BlockHash(ctx context.Context, number uint64) (common.Hash, error) {
+	block, err := c.getBlock(ctx, number)
+	if err != nil {
+		return common.Hash{}, err
+	}
+
+	return block.Hash, nil
+}
+
+func (c *jsonRPCL2NodeClient) SubscribeNewHeads(ctx context.Context, ch chan<- *ethTypes.Header) (ethereum.Subscription, error) {
+	return c.client.SubscribeNewHead(ctx, ch)
+}
+
+func (c *jsonRPCL2NodeClient) BatchNumberByBlockNumber(ctx context.Context, number uint64) (uint64, error) {
+	var batchNumber *hexutil.Uint64
+	err := c.client.Client().CallContext(ctx, &batchNumber, "zkevm_batchNumberByBlockNumber", hexutil.EncodeUint64(number))
+	if err != nil {
+		return 0, err
+	}
+	if batchNumber == nil {
+		return 0, ethereum.NotFound
+	}
+
+	return uint64(*batchNumber), nil
+}
+
+func (c *jsonRPCL2NodeClient) VirtualBatchNumber(ctx context.Context) (uint64, error) {
+	var batchNumber hexutil.Uint64
+	err := c.client.Client().CallContext(ctx, &batchNumber, "zkevm_virtualBatchNumber")
+	return uint64(batchNumber), err
+}
+
+func (c *jsonRPCL2NodeClient) VerifiedBatchNumber(ctx context.Context) (uint64, error) {
+	var batchNumber hexutil.Uint64
+	err := c.client.Client().CallContext(ctx, &batchNumber, "zkevm_verifiedBatchNumber")
+	return uint64(batchNumber), err
+}
+
+func (c *jsonRPCL2NodeClient) ChainID(ctx context.Context) (uint64, error) {
+	return callChainID(ctx, c.client.Client())
+}
+
+func (c *jsonRPCL2NodeClient) Health(ctx context.Context) error {
+	_, err := c.client.BlockNumber(ctx)
+	return err
+}
+
+func (c *jsonRPCL2NodeClient) Close() {
+	c.client.Close()
+}
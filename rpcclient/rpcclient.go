@@ -0,0 +1,159 @@
+package rpcclient
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/0xPolygonHermez/zkevm-pool-manager/hex"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/ethereum/go-ethereum/rpc"
+	"github.com/golang-jwt/jwt/v4"
+)
+
+const jwtSecretLength = 32
+
+// Dial connects to the RPC node at the url with the configured headers and authentication
+func Dial(ctx context.Context, url string, cfg Config) (*ethclient.Client, error) {
+	client, err := dialRPC(ctx, url, cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	return ethclient.NewClient(client), nil
+}
+
+// clientOptions returns the RPC client options for the configured headers and authentication
+func clientOptions(cfg Config) ([]rpc.ClientOption, error) {
+	opts := []rpc.ClientOption{}
+
+	if len(cfg.Headers) > 0 {
+		headers := http.Header{}
+		for name, value := range cfg.Headers {
+			headers.Set(name, value)
+		}
+		opts = append(opts, rpc.WithHeaders(headers))
+	}
+
+	auth, err := newTokenSource(cfg)
+	if err != nil {
+		return nil, err
+	}
+	if auth != nil {
+		opts = append(opts, rpc.WithHTTPAuth(auth.authorize))
+	}
+
+	return opts, nil
+}
+
+// tokenSource provides the token sent in the Authorization header, refreshing it every TokenRefreshInterval
+type tokenSource struct {
+	cfg       Config
+	jwtSecret []byte
+	token     string
+	issuedAt  time.Time
+	mutex     sync.Mutex
+}
+
+// newTokenSource creates the token source for the configured authentication. Returns nil if no authentication is configured
+func newTokenSource(cfg Config) (*tokenSource, error) {
+	configured := 0
+	for _, value := range []string{cfg.BearerToken, cfg.BearerTokenFile, cfg.JWTSecretFile} {
+		if value != "" {
+			configured++
+		}
+	}
+	if configured == 0 {
+		return nil, nil
+	}
+	if configured > 1 {
+		return nil, fmt.Errorf("only one of BearerToken, BearerTokenFile or JWTSecretFile can be configured")
+	}
+
+	t := &tokenSource{cfg: cfg}
+
+	if cfg.JWTSecretFile != "" {
+		secret, err := readJWTSecret(cfg.JWTSecretFile)
+		if err != nil {
+			return nil, err
+		}
+		t.jwtSecret = secret
+	}
+
+	// Get the first token to fail fast if it can't be generated
+	if _, err := t.getToken(); err != nil {
+		return nil, err
+	}
+
+	return t, nil
+}
+
+// authorize sets the Authorization header of the request
+func (t *tokenSource) authorize(h http.Header) error {
+	token, err := t.getToken()
+	if err != nil {
+		return err
+	}
+
+	h.Set("Authorization", "Bearer "+token)
+	return nil
+}
+
+// getToken returns the current token, refreshing it if it's older than TokenRefreshInterval
+func (t *tokenSource) getToken() (string, error) {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	now := time.Now()
+	if t.token != "" && now.Sub(t.issuedAt) < t.cfg.TokenRefreshInterval.Duration {
+		return t.token, nil
+	}
+
+	var token string
+	switch {
+	case t.jwtSecret != nil:
+		signed, err := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{"iat": now.Unix()}).SignedString(t.jwtSecret)
+		if err != nil {
+			return "", fmt.Errorf("error signing JWT token: %w", err)
+		}
+		token = signed
+	case t.cfg.BearerTokenFile != "":
+		data, err := os.ReadFile(t.cfg.BearerTokenFile)
+		if err != nil {
+			return "", fmt.Errorf("error reading bearer token file %s: %w", t.cfg.BearerTokenFile, err)
+		}
+		token = strings.TrimSpace(string(data))
+		if token == "" {
+			return "", fmt.Errorf("bearer token file %s is empty", t.cfg.BearerTokenFile)
+		}
+	default:
+		token = t.cfg.BearerToken
+	}
+
+	t.token = token
+	t.issuedAt = now
+
+	return token, nil
+}
+
+// readJWTSecret reads the hex encoded JWT secret from the file
+func readJWTSecret(path string) ([]byte, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("error reading JWT secret file %s: %w", path, err)
+	}
+
+	secret, err := hex.DecodeHex(strings.TrimSpace(string(data)))
+	if err != nil {
+		return nil, fmt.Errorf("invalid JWT secret in file %s, it must be hex encoded", path)
+	}
+	if len(secret) != jwtSecretLength {
+		return nil, fmt.Errorf("invalid JWT secret in file %s, it must be %d bytes long", path, jwtSecretLength)
+	}
+
+	return secret, nil
+}
@@ -0,0 +1,97 @@
+package rpcclient
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/0xPolygonHermez/zkevm-pool-manager/config/types"
+	"github.com/golang-jwt/jwt/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDialWithHeadersAndJWT(t *testing.T) {
+	secret := strings.Repeat("ab", jwtSecretLength)
+	secretFile := filepath.Join(t.TempDir(), "jwt.hex")
+	require.NoError(t, os.WriteFile(secretFile, []byte("0x"+secret+"\n"), 0600))
+
+	var authorization, customHeader string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		authorization = r.Header.Get("Authorization")
+		customHeader = r.Header.Get("X-Custom")
+
+		var req struct {
+			ID json.RawMessage `json:"id"`
+		}
+		_ = json.NewDecoder(r.Body).Decode(&req)
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = fmt.Fprintf(w, `{"jsonrpc":"2.0","id":%s,"result":"0x3e9"}`, req.ID)
+	}))
+	defer server.Close()
+
+	cfg := Config{
+		Headers:              map[string]string{"x-custom": "custom-value"},
+		JWTSecretFile:        secretFile,
+		TokenRefreshInterval: types.NewDuration(time.Minute),
+	}
+
+	client, err := Dial(context.Background(), server.URL, cfg)
+	require.NoError(t, err)
+
+	chainID, err := client.ChainID(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, uint64(1001), chainID.Uint64())
+	assert.Equal(t, "custom-value", customHeader)
+
+	require.True(t, strings.HasPrefix(authorization, "Bearer "))
+	token, err := jwt.Parse(strings.TrimPrefix(authorization, "Bearer "), func(token *jwt.Token) (interface{}, error) {
+		return []byte(strings.Repeat("\xab", jwtSecretLength)), nil
+	})
+	require.NoError(t, err)
+	assert.True(t, token.Valid)
+	assert.Equal(t, jwt.SigningMethodHS256, token.Method)
+}
+
+func TestBearerTokenFileRefresh(t *testing.T) {
+	tokenFile := filepath.Join(t.TempDir(), "token")
+	require.NoError(t, os.WriteFile(tokenFile, []byte("token1\n"), 0600))
+
+	source, err := newTokenSource(Config{BearerTokenFile: tokenFile, TokenRefreshInterval: types.NewDuration(time.Hour)})
+	require.NoError(t, err)
+
+	token, err := source.getToken()
+	require.NoError(t, err)
+	assert.Equal(t, "token1", token)
+
+	// The token is not read again until the refresh interval has elapsed
+	require.NoError(t, os.WriteFile(tokenFile, []byte("token2"), 0600))
+	token, err = source.getToken()
+	require.NoError(t, err)
+	assert.Equal(t, "token1", token)
+
+	source.issuedAt = time.Now().Add(-2 * time.Hour)
+	token, err = source.getToken()
+	require.NoError(t, err)
+	assert.Equal(t, "token2", token)
+}
+
+func TestConfigStringRedactsSecrets(t *testing.T) {
+	cfg := Config{
+		Headers:     map[string]string{"x-api-key": "secret-header"},
+		BearerToken: "secret-token",
+	}
+
+	for _, s := range []string{cfg.String(), fmt.Sprintf("%v", cfg), fmt.Sprintf("%+v", struct{ Auth Config }{cfg})} {
+		assert.NotContains(t, s, "secret-header")
+		assert.NotContains(t, s, "secret-token")
+		assert.Contains(t, s, "x-api-key")
+	}
+}
@@ -0,0 +1,89 @@
+package rpcclient
+
+import (
+	"context"
+
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+// SequencerClient is the client used to send the txs to the sequencer
+type SequencerClient interface {
+	// SendRawTransaction sends the encoded tx to the sequencer
+	SendRawTransaction(ctx context.Context, encoded string) error
+	// SendRawTransactions sends the encoded txs to the sequencer in a single request. It returns the result of each tx,
+	// or an error if the request failed
+	SendRawTransactions(ctx context.Context, encoded []string) ([]error, error)
+	// ChainID returns the chain id of the sequencer
+	ChainID(ctx context.Context) (uint64, error)
+	// Health returns an error if the sequencer is not able to process requests
+	Health(ctx context.Context) error
+	// Close closes the connection to the sequencer
+	Close()
+}
+
+// jsonRPCSequencerClient is a SequencerClient that uses JSON-RPC over HTTP, WebSocket or IPC
+type jsonRPCSequencerClient struct {
+	client *rpc.Client
+}
+
+// DialSequencer connects to the sequencer at the url using the transport of the url scheme
+func DialSequencer(ctx context.Context, url string, cfg Config) (SequencerClient, error) {
+	client, err := dialRPC(ctx, url, cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	return &jsonRPCSequencerClient{client: client}, nil
+}
+
+func (c *jsonRPCSequencerClient) SendRawTransaction(ctx context.Context, encoded string) error {
+	return c.client.CallContext(ctx, nil, "eth_sendRawTransaction", encoded)
+}
+
+func (c *jsonRPCSequencerClient) SendRawTransactions(ctx context.Context, encoded []string) ([]error, error) {
+	elems := make([]rpc.BatchElem, len(encoded))
+	for i, tx := range encoded {
+		elems[i] = rpc.BatchElem{
+			Method: "eth_sendRawTransaction",
+			Args:   []interface{}{tx},
+			Result: new(string),
+		}
+	}
+
+	err := c.client.BatchCallContext(ctx, elems)
+	if err != nil {
+		return nil, err
+	}
+
+	errs := make([]error, len(elems))
+	for i := range elems {
+		errs[i] = elems[i].Error
+	}
+
+	return errs, nil
+}
+
+func (c *jsonRPCSequencerClient) ChainID(ctx context.Context) (uint64, error) {
+	return callChainID(ctx, c.client)
+}
+
+func (c *jsonRPCSequencerClient) Health(ctx context.Context) error {
+	var blockNumber hexutil.Uint64
+	return c.client.CallContext(ctx, &blockNumber, "eth_blockNumber")
+}
+
+func (c *jsonRPCSequencerClient) Close() {
+	c.client.Close()
+}
+
+// callChainID returns the chain id of the RPC node
+func callChainID(ctx context.Context, client *rpc.Client) (uint64, error) {
+	var chainID hexutil.Uint64
+	err := client.CallContext(ctx, &chainID, "eth_chainId")
+	if err != nil {
+		return 0, err
+	}
+
+	return uint64(chainID), nil
+}
@@ -0,0 +1,70 @@
+package rpcclient
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+const (
+	// TransportHTTP is the JSON-RPC over HTTP transport (http:// and https:// urls)
+	TransportHTTP = "http"
+	// TransportWebSocket is the JSON-RPC over WebSocket transport (ws:// and wss:// urls)
+	TransportWebSocket = "ws"
+	// TransportIPC is the JSON-RPC over IPC transport (urls without scheme, that are the path of the IPC socket)
+	TransportIPC = "ipc"
+)
+
+// Transport returns the transport used to connect to the url
+func Transport(rawurl string) (string, error) {
+	u, err := url.Parse(rawurl)
+	if err != nil {
+		return "", fmt.Errorf("invalid url %s: %w", rawurl, err)
+	}
+
+	switch u.Scheme {
+	case "http", "https":
+		return TransportHTTP, nil
+	case "ws", "wss":
+		return TransportWebSocket, nil
+	case "":
+		return TransportIPC, nil
+	default:
+		return "", fmt.Errorf("no known transport for url %s", rawurl)
+	}
+}
+
+// dialRPC connects to the RPC node at the url using the transport of the url scheme
+func dialRPC(ctx context.Context, rawurl string, cfg Config) (*rpc.Client, error) {
+	transport, err := Transport(rawurl)
+	if err != nil {
+		return nil, err
+	}
+
+	if transport == TransportIPC {
+		return dialIPC(ctx, rawurl, cfg)
+	}
+	return dialHTTPOrWebSocket(ctx, rawurl, cfg)
+}
+
+// dialHTTPOrWebSocket connects to the RPC node using HTTP or WebSocket, as selected by the url scheme. The configured
+// headers and authentication are sent in each HTTP request, or in the WebSocket handshake
+func dialHTTPOrWebSocket(ctx context.Context, rawurl string, cfg Config) (*rpc.Client, error) {
+	opts, err := clientOptions(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	return rpc.DialOptions(ctx, rawurl, opts...)
+}
+
+// dialIPC connects to the RPC node using the IPC socket at the path. Headers and authentication are not supported
+func dialIPC(ctx context.Context, path string, cfg Config) (*rpc.Client, error) {
+	if len(cfg.Headers) > 0 || cfg.BearerToken != "" || cfg.BearerTokenFile != "" || cfg.JWTSecretFile != "" {
+		return nil, fmt.Errorf("headers and authentication are not supported for IPC connections (%s)", path)
+	}
+
+	return rpc.DialIPC(ctx, path)
+}
@@ -0,0 +1,120 @@
+package rpcclient
+
+import (
+	"context"
+	"net"
+	"path/filepath"
+	"testing"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/rpc"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// ethService is a minimal eth namespace served by the in-process test node
+type ethService struct {
+	sent []string
+}
+
+func (s *ethService) ChainId() hexutil.Uint64 {
+	return 1001
+}
+
+func (s *ethService) BlockNumber() hexutil.Uint64 {
+	return 1
+}
+
+func (s *ethService) SendRawTransaction(encoded string) string {
+	s.sent = append(s.sent, encoded)
+	return "0x01"
+}
+
+// zkevmService is a minimal zkevm namespace served by the in-process test node, the blocks not in a batch return null
+type zkevmService struct {
+	batches map[uint64]uint64
+}
+
+func (s *zkevmService) BatchNumberByBlockNumber(number hexutil.Uint64) *hexutil.Uint64 {
+	batchNumber, found := s.batches[uint64(number)]
+	if !found {
+		return nil
+	}
+	return (*hexutil.Uint64)(&batchNumber)
+}
+
+func TestTransport(t *testing.T) {
+	transports := map[string]string{
+		"http://localhost:8545":  TransportHTTP,
+		"https://localhost:8545": TransportHTTP,
+		"ws://localhost:8546":    TransportWebSocket,
+		"wss://localhost:8546":   TransportWebSocket,
+		"/tmp/sequencer.ipc":     TransportIPC,
+	}
+
+	for url, expected := range transports {
+		transport, err := Transport(url)
+		require.NoError(t, err)
+		assert.Equal(t, expected, transport, url)
+	}
+
+	_, err := Transport("ftp://localhost")
+	assert.Error(t, err)
+}
+
+func TestDialSequencerIPC(t *testing.T) {
+	service := &ethService{}
+	server := rpc.NewServer()
+	require.NoError(t, server.RegisterName("eth", service))
+	defer server.Stop()
+
+	path := filepath.Join(t.TempDir(), "sequencer.ipc")
+	listener, err := net.Listen("unix", path)
+	require.NoError(t, err)
+	go server.ServeListener(listener) //nolint:errcheck
+	defer listener.Close()
+
+	client, err := DialSequencer(context.Background(), path, Config{})
+	require.NoError(t, err)
+	defer client.Close()
+
+	require.NoError(t, client.Health(context.Background()))
+	chainID, err := client.ChainID(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, uint64(1001), chainID)
+
+	require.NoError(t, client.SendRawTransaction(context.Background(), "0xaa"))
+	errs, err := client.SendRawTransactions(context.Background(), []string{"0xbb", "0xcc"})
+	require.NoError(t, err)
+	assert.Equal(t, []error{nil, nil}, errs)
+	assert.Equal(t, []string{"0xaa", "0xbb", "0xcc"}, service.sent)
+
+	_, err = DialSequencer(context.Background(), path, Config{BearerToken: "token"})
+	assert.Error(t, err)
+}
+
+func TestL2NodeBatchNumberByBlockNumber(t *testing.T) {
+	server := rpc.NewServer()
+	require.NoError(t, server.RegisterName("eth", &ethService{}))
+	require.NoError(t, server.RegisterName("zkevm", &zkevmService{batches: map[uint64]uint64{10: 5}}))
+	defer server.Stop()
+
+	path := filepath.Join(t.TempDir(), "l2node.ipc")
+	listener, err := net.Listen("unix", path)
+	require.NoError(t, err)
+	go server.ServeListener(listener) //nolint:errcheck
+	defer listener.Close()
+
+	client, err := DialL2Node(context.Background(), path, Config{})
+	require.NoError(t, err)
+	defer client.Close()
+
+	batchNumber, err := client.BatchNumberByBlockNumber(context.Background(), 10)
+	require.NoError(t, err)
+	assert.Equal(t, uint64(5), batchNumber)
+
+	// The null result of a block not included in a batch yet is not batch 0
+	_, err = client.BatchNumberByBlockNumber(context.Background(), 11)
+	assert.ErrorIs(t, err, ethereum.NotFound)
+}
@@ -1,15 +1,35 @@
 package sender
 
-import "github.com/0xPolygonHermez/zkevm-pool-manager/config/types"
+import (
+	"github.com/0xPolygonHermez/zkevm-pool-manager/config/types"
+	"github.com/0xPolygonHermez/zkevm-pool-manager/limiter"
+	"github.com/0xPolygonHermez/zkevm-pool-manager/rpcclient"
+	"github.com/0xPolygonHermez/zkevm-pool-manager/supervisor"
+)
 
 // Config for pool-manager sender
 type Config struct {
-	// SequencerURL defines the URL for the sequencer RPC where the sender will send the pending txs
+	// SequencerURL defines the URL for the sequencer RPC where the sender will send the pending txs. The transport is
+	// selected by the URL scheme: http(s):// for HTTP, ws(s):// for WebSocket or the path of the socket for IPC
 	SequencerURL string `mapstructure:"SequencerURL"`
 
+	// SequencerAuth is the configuration of the headers and authentication used to connect to the sequencer
+	SequencerAuth rpcclient.Config `mapstructure:"SequencerAuth"`
+
 	// ResendTxsCheckInterval is the time the sender waits to check in there are new txs in the pool
 	ResendTxsCheckInterval types.Duration `mapstructure:"ResendTxsCheckInterval"`
 
+	// NotificationsEnabled defines if the sender listens for the pool database notifications of txs that change to resend
+	// status or pending txs inserted by other components, to process them immediately. When enabled ResendTxsCheckInterval
+	// can be increased as the polling is only a safety net
+	NotificationsEnabled bool `mapstructure:"NotificationsEnabled"`
+
+	// PendingTxsCheckInterval is the time the sender waits between checks for pending txs that have not been sent (0 = disabled)
+	PendingTxsCheckInterval types.Duration `mapstructure:"PendingTxsCheckInterval"`
+
+	// PendingTxsMinAge is the time since a tx was received after which a pending tx is considered stuck and it's sent again
+	PendingTxsMinAge types.Duration `mapstructure:"PendingTxsMinAge"`
+
 	// Workers is the number of sender workers to send txs to the sequencer
 	Workers uint16 `mapstructure:"Workers"`
 
@@ -18,4 +38,83 @@ type Config struct {
 
 	// RPCReadTimeout is the timeout for the RPC client to read the response from the L2 node
 	RPCReadTimeout types.Duration `mapstructure:"RPCReadTimeout"`
+
+	// MaxSendAttempts is the max number of times a tx is sent to the sequencer before it's dropped (0 = no limit)
+	MaxSendAttempts uint64 `mapstructure:"MaxSendAttempts"`
+
+	// MaxResendTime is the max time since the first send of a tx during which it can be resent before it's dropped (0 = no limit)
+	MaxResendTime types.Duration `mapstructure:"MaxResendTime"`
+
+	// Supervisor is the configuration of the supervision of the sender workers
+	Supervisor supervisor.Config `mapstructure:"Supervisor"`
+
+	// FairQueue is the configuration for the fair queuing of the txs to send across clients
+	FairQueue FairQueueConfig `mapstructure:"FairQueue"`
+
+	// BatchSend is the configuration to send the txs to the sequencer using JSON-RPC batch requests
+	BatchSend BatchSendConfig `mapstructure:"BatchSend"`
+
+	// Shadow is the configuration to mirror the sent txs to a secondary sequencer
+	Shadow ShadowConfig `mapstructure:"Shadow"`
+
+	// RateLimit is the configuration of the limits of the calls to the sequencer
+	RateLimit limiter.Config `mapstructure:"RateLimit"`
+}
+
+// FairQueueConfig for the fair queuing of the txs to send across clients
+type FairQueueConfig struct {
+	// Enabled defines if the txs to send are dispatched to the workers using a weighted round-robin across clients
+	Enabled bool `mapstructure:"Enabled"`
+
+	// KeyType defines how the clients are identified ("ip", "from" or "apikey")
+	KeyType string `mapstructure:"KeyType" jsonschema:"enum=ip,enum=from,enum=apikey"`
+
+	// DefaultWeight is the number of txs a client can dispatch in each round if it has no specific weight configured
+	DefaultWeight uint16 `mapstructure:"DefaultWeight"`
+
+	// Weights defines specific weights for some clients
+	Weights []ClientWeight `mapstructure:"Weights"`
+}
+
+// ClientWeight defines the weight of a client for the fair queuing
+type ClientWeight struct {
+	// Key is the client IP, sender address or API key (depending on the FairQueue.KeyType)
+	Key string `mapstructure:"Key"`
+
+	// Weight is the number of txs the client can dispatch in each round
+	Weight uint16 `mapstructure:"Weight"`
+}
+
+// BatchSendConfig for sending the txs to the sequencer using JSON-RPC batch requests
+type BatchSendConfig struct {
+	// Enabled defines if the sender workers coalesce the queued txs into JSON-RPC batch requests
+	Enabled bool `mapstructure:"Enabled"`
+
+	// MaxSize is the maximum number of txs sent in a single batch request
+	MaxSize uint16 `mapstructure:"MaxSize"`
+
+	// MaxLinger is the maximum time a worker waits for more txs to fill the batch request before sending it
+	MaxLinger types.Duration `mapstructure:"MaxLinger"`
+}
+
+// ShadowConfig for mirroring the sent txs to a secondary sequencer (e.g. a canary build), without affecting the txs status
+type ShadowConfig struct {
+	// Enabled defines if the txs sent to the sequencer are also sent to the shadow sequencer
+	Enabled bool `mapstructure:"Enabled"`
+
+	// SequencerURL defines the URL for the shadow sequencer RPC
+	SequencerURL string `mapstructure:"SequencerURL"`
+
+	// SequencerAuth is the configuration of the headers and authentication used to connect to the shadow sequencer
+	SequencerAuth rpcclient.Config `mapstructure:"SequencerAuth"`
+
+	// Workers is the number of workers to send txs to the shadow sequencer
+	Workers uint16 `mapstructure:"Workers"`
+
+	// QueueSize is the size of the queue for txs to send to the shadow sequencer. If the queue is full the txs are not
+	// mirrored, so the shadow sequencer never slows down the sender
+	QueueSize uint16 `mapstructure:"QueueSize"`
+
+	// RPCReadTimeout is the timeout for the RPC client to read the response from the shadow sequencer
+	RPCReadTimeout types.Duration `mapstructure:"RPCReadTimeout"`
 }
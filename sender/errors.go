@@ -0,0 +1,50 @@
+package sender
+
+import (
+	"errors"
+	"strings"
+)
+
+var (
+	// ErrAlreadyInFlight is returned when a tx is requested to be sent while it's already being sent by the sender
+	ErrAlreadyInFlight = errors.New("tx is already being sent")
+	// ErrSenderStopped is returned when a tx is requested to be sent while the sender is stopping
+	ErrSenderStopped = errors.New("sender is stopped")
+	// ErrSendLimitReached is returned when a tx is requested to be sent after reaching the max number of send attempts or
+	// the max resend time, then the tx is dropped
+	ErrSendLimitReached = errors.New("send limit reached")
+	// ErrBatchRequestFailed is returned for the txs of a batch request that failed as a whole (i.e. a transport error),
+	// so the sequencer has not processed the txs
+	ErrBatchRequestFailed = errors.New("batch request to the sequencer failed")
+)
+
+// alreadyKnownErrors are the sequencer errors returned when the tx is already in the sequencer pool
+var alreadyKnownErrors = []string{"already known", "known transaction"}
+
+// nonceTooLowErrors are the sequencer errors returned when the nonce of the tx has already been used, that for a
+// resent tx means it has already been mined
+var nonceTooLowErrors = []string{"nonce too low"}
+
+// isAlreadyKnownError returns true if the sequencer returned the error because it already has the tx
+func isAlreadyKnownError(err error) bool {
+	return matchError(err, alreadyKnownErrors)
+}
+
+// isNonceTooLowError returns true if the sequencer returned the error because the nonce of the tx has already been used
+func isNonceTooLowError(err error) bool {
+	return matchError(err, nonceTooLowErrors)
+}
+
+func matchError(err error, messages []string) bool {
+	if err == nil {
+		return false
+	}
+
+	errMsg := strings.ToLower(err.Error())
+	for _, msg := range messages {
+		if strings.Contains(errMsg, msg) {
+			return true
+		}
+	}
+	return false
+}
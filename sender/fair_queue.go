@@ -0,0 +1,143 @@
+package sender
+
+import (
+	"strings"
+	"sync"
+
+	"github.com/0xPolygonHermez/zkevm-pool-manager/types"
+)
+
+const (
+	// FairQueueKeyIP groups the send requests by the client IP
+	FairQueueKeyIP = "ip"
+	// FairQueueKeyFromAddress groups the send requests by the tx sender address
+	FairQueueKeyFromAddress = "from"
+	// FairQueueKeyAPIKey groups the send requests by the API key used by the client
+	FairQueueKeyAPIKey = "apikey"
+)
+
+// fairQueue holds the send requests grouped by client and dispatches them using a weighted deficit round-robin,
+// so a single client sending a lot of txs can't monopolize the sender workers
+type fairQueue struct {
+	cfg     FairQueueConfig
+	weights map[string]uint16
+	clients map[string]*clientQueue
+	active  []*clientQueue
+	next    int
+	len     int
+	closed  bool
+	cond    *sync.Cond
+}
+
+// clientQueue is the queue of send requests of a single client
+type clientQueue struct {
+	key      string
+	requests []*sendRequest
+	deficit  uint16
+}
+
+// newFairQueue creates and init a fairQueue
+func newFairQueue(cfg FairQueueConfig) *fairQueue {
+	weights := make(map[string]uint16, len(cfg.Weights))
+	for _, w := range cfg.Weights {
+		weights[strings.ToLower(w.Key)] = w.Weight
+	}
+
+	return &fairQueue{
+		cfg:     cfg,
+		weights: weights,
+		clients: make(map[string]*clientQueue),
+		cond:    sync.NewCond(&sync.Mutex{}),
+	}
+}
+
+// push adds a send request to the queue of its client
+func (q *fairQueue) push(request *sendRequest) {
+	q.cond.L.Lock()
+	defer q.cond.L.Unlock()
+
+	key := q.key(&request.l2Tx)
+	client, found := q.clients[key]
+	if !found {
+		client = &clientQueue{key: key}
+		q.clients[key] = client
+		q.active = append(q.active, client)
+	}
+	client.requests = append(client.requests, request)
+	q.len++
+
+	q.cond.Signal()
+}
+
+// pop returns the next send request to process, waiting until there is one available. Returns nil if the queue is closed
+func (q *fairQueue) pop() *sendRequest {
+	q.cond.L.Lock()
+	defer q.cond.L.Unlock()
+
+	for q.len == 0 && !q.closed {
+		q.cond.Wait()
+	}
+	if q.closed {
+		return nil
+	}
+
+	client := q.active[q.next]
+	if client.deficit == 0 {
+		client.deficit = q.weight(client.key)
+	}
+
+	request := client.requests[0]
+	client.requests[0] = nil
+	client.requests = client.requests[1:]
+	client.deficit--
+	q.len--
+
+	if len(client.requests) == 0 {
+		// client has no more requests, remove it from the round-robin
+		delete(q.clients, client.key)
+		copy(q.active[q.next:], q.active[q.next+1:])
+		q.active[len(q.active)-1] = nil
+		q.active = q.active[:len(q.active)-1]
+	} else if client.deficit == 0 {
+		// client has consumed its quantum for this round, move to the next client
+		q.next++
+	}
+
+	if q.next >= len(q.active) {
+		q.next = 0
+	}
+
+	return request
+}
+
+// close closes the queue, waking up the waiting pop calls
+func (q *fairQueue) close() {
+	q.cond.L.Lock()
+	defer q.cond.L.Unlock()
+
+	q.closed = true
+	q.cond.Broadcast()
+}
+
+// key returns the client key used to group the l2Tx
+func (q *fairQueue) key(l2Tx *types.L2Transaction) string {
+	switch q.cfg.KeyType {
+	case FairQueueKeyFromAddress:
+		return strings.ToLower(l2Tx.FromAddress)
+	case FairQueueKeyAPIKey:
+		return strings.ToLower(l2Tx.APIKey)
+	default:
+		return strings.ToLower(l2Tx.IP)
+	}
+}
+
+// weight returns the number of requests the client can dispatch in each round
+func (q *fairQueue) weight(key string) uint16 {
+	if weight, found := q.weights[key]; found && weight > 0 {
+		return weight
+	}
+	if q.cfg.DefaultWeight > 0 {
+		return q.cfg.DefaultWeight
+	}
+	return 1
+}
@@ -0,0 +1,55 @@
+package sender
+
+import (
+	"testing"
+
+	"github.com/0xPolygonHermez/zkevm-pool-manager/types"
+)
+
+func TestFairQueueRoundRobin(t *testing.T) {
+	q := newFairQueue(FairQueueConfig{
+		Enabled:       true,
+		KeyType:       FairQueueKeyIP,
+		DefaultWeight: 1,
+		Weights:       []ClientWeight{{Key: "10.0.0.3", Weight: 2}},
+	})
+
+	// Client 10.0.0.1 is a noisy client that enqueues its txs first
+	for i := uint64(1); i <= 4; i++ {
+		q.push(&sendRequest{l2Tx: types.L2Transaction{Id: i, IP: "10.0.0.1"}})
+	}
+	q.push(&sendRequest{l2Tx: types.L2Transaction{Id: 5, IP: "10.0.0.2"}})
+	q.push(&sendRequest{l2Tx: types.L2Transaction{Id: 6, IP: "10.0.0.3"}})
+	q.push(&sendRequest{l2Tx: types.L2Transaction{Id: 7, IP: "10.0.0.3"}})
+	q.push(&sendRequest{l2Tx: types.L2Transaction{Id: 8, IP: "10.0.0.3"}})
+
+	expected := []uint64{1, 5, 6, 7, 2, 8, 3, 4}
+
+	for index, id := range expected {
+		request := q.pop()
+		if request.l2Tx.Id != id {
+			t.Fatalf("Dispatch order error at index %d. Expected %d, Actual %d", index, id, request.l2Tx.Id)
+		}
+	}
+
+	if q.len != 0 || len(q.active) != 0 || len(q.clients) != 0 {
+		t.Fatalf("Length error. Queue should be empty, len: %d, active: %d, clients: %d", q.len, len(q.active), len(q.clients))
+	}
+}
+
+func TestFairQueueKey(t *testing.T) {
+	l2Tx := &types.L2Transaction{IP: "10.0.0.1", FromAddress: "0xAbC", APIKey: "Key1"}
+
+	keys := map[string]string{
+		FairQueueKeyIP:          "10.0.0.1",
+		FairQueueKeyFromAddress: "0xabc",
+		FairQueueKeyAPIKey:      "key1",
+	}
+
+	for keyType, expected := range keys {
+		q := newFairQueue(FairQueueConfig{KeyType: keyType})
+		if key := q.key(l2Tx); key != expected {
+			t.Fatalf("Key error for %s. Expected %s, Actual %s", keyType, expected, key)
+		}
+	}
+}
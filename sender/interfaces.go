@@ -2,6 +2,7 @@ package sender
 
 import (
 	"context"
+	"time"
 
 	"github.com/0xPolygonHermez/zkevm-pool-manager/types"
 )
@@ -10,6 +11,18 @@ type poolDBInterface interface {
 	UpdateL2TransactionStatus(ctx context.Context, id uint64, newStatus string, errorMsg string) error
 	GetL2TransactionsToResend(ctx context.Context) ([]*types.L2Transaction, error)
 	GetL2TransactionsToSend(ctx context.Context) ([]*types.L2Transaction, error)
+	GetL2TransactionsToRecover(ctx context.Context, receivedBefore time.Time) ([]*types.L2Transaction, error)
+	ClaimL2TransactionToRecover(ctx context.Context, id uint64, updatedBefore time.Time) (*types.L2Transaction, error)
+	GetL2TransactionsToSendByID(ctx context.Context, id uint64) ([]*types.L2Transaction, error)
+	ListenL2TransactionNotifications(ctx context.Context, notifications chan<- types.L2TransactionNotification) error
+	OwnerID() string
+	UpdateL2TransactionSendAttempt(ctx context.Context, id uint64, sentAt time.Time, errorMsg string) error
+	AddShadowSendResult(ctx context.Context, result *types.ShadowSendResult) error
+}
+
+type leaderInterface interface {
+	IsLeader() bool
+	OnAcquired(fn func())
 }
 
 type monitorInterface interface {
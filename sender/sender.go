@@ -2,12 +2,17 @@ package sender
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"runtime/debug"
 	"sync"
 	"time"
 
+	"github.com/0xPolygonHermez/zkevm-pool-manager/limiter"
 	"github.com/0xPolygonHermez/zkevm-pool-manager/log"
+	"github.com/0xPolygonHermez/zkevm-pool-manager/rpcclient"
+	"github.com/0xPolygonHermez/zkevm-pool-manager/supervisor"
 	"github.com/0xPolygonHermez/zkevm-pool-manager/types"
-	"github.com/ethereum/go-ethereum/ethclient"
 	"github.com/jackc/pgx/v4"
 )
 
@@ -15,37 +20,173 @@ type Sender struct {
 	cfg         Config
 	poolDB      poolDBInterface
 	monitor     monitorInterface
+	leader      leaderInterface
+	workers     *supervisor.Supervisor
 	requestChan chan *sendRequest
+	fairQueue   *fairQueue
+	shadow      *shadowSender
+	limiter     *limiter.Limiter
+	// inFlight holds the ids of the txs the sender is currently sending, to avoid duplicated sends of the same tx
+	inFlight     map[uint64]struct{}
+	inFlightCond *sync.Cond
+	// stopped is set when the sender is stopping, then no new txs are accepted to be sent
+	stopped bool
+	// stopWorkers stops the sender workers
+	stopWorkers context.CancelFunc
+	// resendWakeChan wakes up the resend loop when a tx changes to resend status
+	resendWakeChan chan struct{}
+	// dialSequencer creates the clients used by the workers to send the txs to the sequencer
+	dialSequencer dialSequencerFunc
 }
 
+// dialSequencerFunc creates a client to send txs to the sequencer at the url
+type dialSequencerFunc func(ctx context.Context, url string, cfg rpcclient.Config) (rpcclient.SequencerClient, error)
+
 type sendRequest struct {
 	l2Tx types.L2Transaction
 	wg   *sync.WaitGroup
 	err  error
+	// sentAt is the time the tx was sent to the sequencer and latency is the time the sequencer took to respond
+	sentAt  time.Time
+	latency time.Duration
 }
 
-func NewSender(cfg Config, poolDB poolDBInterface, monitor monitorInterface) *Sender {
-	return &Sender{
+func NewSender(cfg Config, poolDB poolDBInterface, monitor monitorInterface, leader leaderInterface) *Sender {
+	s := &Sender{
 		cfg:         cfg,
 		poolDB:      poolDB,
 		monitor:     monitor,
-		requestChan: make(chan *sendRequest, cfg.QueueSize)}
+		leader:      leader,
+		workers:     supervisor.NewSupervisor("sender", cfg.Supervisor),
+		requestChan: make(chan *sendRequest, cfg.QueueSize),
+		inFlight:    make(map[uint64]struct{}),
+		limiter:     limiter.NewLimiter("sender", cfg.RateLimit),
+
+		inFlightCond: sync.NewCond(&sync.Mutex{}),
+
+		resendWakeChan: make(chan struct{}, 1),
+
+		dialSequencer: rpcclient.DialSequencer,
+	}
+
+	if cfg.FairQueue.Enabled {
+		s.fairQueue = newFairQueue(cfg.FairQueue)
+	}
+
+	if cfg.Shadow.Enabled {
+		s.shadow = newShadowSender(cfg.Shadow, cfg.Supervisor, poolDB, s.dialSequencer)
+	}
+
+	return s
 }
 
-func (s *Sender) Start() {
+// Start starts the sender workers and the background loops. The background loops run until the context is done, the
+// workers run until the sender is stopped, so they can finish sending the in-flight txs
+func (s *Sender) Start(ctx context.Context) {
 	log.Infof("starting %d sender workers", s.cfg.Workers)
 
+	workersCtx, stopWorkers := context.WithCancel(context.Background())
+	s.stopWorkers = stopWorkers
 	for i := 0; i < int(s.cfg.Workers); i++ {
-		go s.startSenderWorker(i)
+		s.workers.Go(workersCtx, i, s.runSenderWorker)
+	}
+
+	if s.fairQueue != nil {
+		log.Infof("fair queuing enabled, clients identified by %s", s.cfg.FairQueue.KeyType)
+		go s.dispatchFairQueue(workersCtx)
+	}
+
+	if s.shadow != nil {
+		s.shadow.start(workersCtx)
+	}
+
+	go s.checkL2TransactionsToResend(ctx)
+
+	if s.cfg.NotificationsEnabled {
+		go s.listenL2TransactionNotifications(ctx)
+	}
+
+	if s.poolDB.OwnerID() != "" {
+		// With leases each instance sends its own pending txs and the ones not owned by running instances
+		log.Infof("sending txs from the pool database")
+		s.sendL2TransactionsFromPoolDB(ctx)
+	} else {
+		// Without leases the leader sends the pending txs of the pool database each time it gets the leadership
+		s.leader.OnAcquired(func() {
+			log.Infof("sending txs from the pool database")
+			go s.sendL2TransactionsFromPoolDB(ctx)
+		})
+	}
+
+	if s.cfg.PendingTxsCheckInterval.Duration > 0 {
+		go s.checkPendingL2Transactions(ctx)
+	}
+}
+
+// Stop stops accepting new txs to send, waits for the in-flight txs to be sent and stops the workers. The txs that
+// have not been sent are kept as pending in the pool database and they will be sent in the next start. If the context
+// is done before all the in-flight txs are sent, it returns an error
+func (s *Sender) Stop(ctx context.Context) error {
+	log.Infof("stopping sender")
+
+	s.inFlightCond.L.Lock()
+	s.stopped = true
+	s.inFlightCond.L.Unlock()
+
+	stop := context.AfterFunc(ctx, func() {
+		s.inFlightCond.L.Lock()
+		defer s.inFlightCond.L.Unlock()
+		s.inFlightCond.Broadcast()
+	})
+	defer stop()
+
+	s.inFlightCond.L.Lock()
+	for len(s.inFlight) > 0 && ctx.Err() == nil {
+		s.inFlightCond.Wait()
 	}
+	inFlight := len(s.inFlight)
+	s.inFlightCond.L.Unlock()
 
-	go s.checkL2TransactionsToResend()
+	if inFlight > 0 {
+		return fmt.Errorf("%d txs still in flight, error: %w", inFlight, ctx.Err())
+	}
 
-	log.Infof("sending txs from the pool database")
-	s.sendL2TransactionsFromPoolDB()
+	if s.stopWorkers != nil {
+		s.stopWorkers()
+	}
+	if s.fairQueue != nil {
+		s.fairQueue.close()
+	}
+	if s.shadow != nil {
+		if err := s.shadow.wait(ctx); err != nil {
+			log.Warnf("error waiting shadow sender workers, error: %v", err)
+		}
+	}
+
+	return s.workers.Wait(ctx)
 }
 
 func (s *Sender) SendL2Transaction(l2Tx *types.L2Transaction) error {
+	if err := s.acquireL2Transaction(l2Tx.Id); err != nil {
+		return err
+	}
+	defer s.releaseL2Transaction(l2Tx.Id)
+
+	return s.sendL2Transaction(l2Tx)
+}
+
+// sendL2Transaction sends the tx to the sequencer and updates its status in the pool db. The tx must be acquired by the
+// caller
+func (s *Sender) sendL2Transaction(l2Tx *types.L2Transaction) error {
+	if limitReached, reason := s.isSendLimitReached(l2Tx); limitReached {
+		log.Infof("tx %s will not be sent, %s", l2Tx.Tag(), reason)
+		err := s.poolDB.UpdateL2TransactionStatus(context.Background(), l2Tx.Id, types.TxStatusDropped, reason)
+		if err != nil {
+			log.Errorf("error updating tx %s status (%s) in the pool db, error: %v", l2Tx.Tag(), types.TxStatusDropped, err)
+		}
+		return fmt.Errorf("%w, %s", ErrSendLimitReached, reason)
+	}
+
 	request := &sendRequest{
 		l2Tx: *l2Tx,
 		wg:   new(sync.WaitGroup),
@@ -55,8 +196,25 @@ func (s *Sender) SendL2Transaction(l2Tx *types.L2Transaction) error {
 	s.enqueueSenderRequest(request)
 	request.wg.Wait()
 
-	if request.err != nil {
-		err := s.poolDB.UpdateL2TransactionStatus(context.Background(), l2Tx.Id, types.TxStatusInvalid, request.err.Error())
+	if s.shadow != nil {
+		s.shadow.send(request)
+	}
+
+	sendErr := request.err
+	if s.isAlreadySent(l2Tx, sendErr) {
+		log.Infof("tx %s already sent to the sequencer, error: %v", l2Tx.Tag(), sendErr)
+		sendErr = nil
+	}
+
+	s.recordSendAttempt(l2Tx, sendErr)
+
+	if errors.Is(sendErr, ErrBatchRequestFailed) {
+		err := s.poolDB.UpdateL2TransactionStatus(context.Background(), l2Tx.Id, types.TxStatusResend, sendErr.Error())
+		if err != nil {
+			log.Errorf("error updating tx %s status (%s) in the pool db, error: %v", l2Tx.Tag(), types.TxStatusResend, err)
+		}
+	} else if sendErr != nil {
+		err := s.poolDB.UpdateL2TransactionStatus(context.Background(), l2Tx.Id, types.TxStatusInvalid, sendErr.Error())
 		if err != nil {
 			log.Errorf("error updating tx %s status (%s) in the pool db, error: %v", l2Tx.Tag(), types.TxStatusInvalid, err)
 		}
@@ -68,46 +226,293 @@ func (s *Sender) SendL2Transaction(l2Tx *types.L2Transaction) error {
 		s.monitor.AddL2Transaction(l2Tx)
 	}
 
-	return request.err
+	return sendErr
+}
+
+// isAlreadySent returns true if the sequencer returned the error because it already has the tx, or because the tx is
+// being resent and its nonce has already been used, as it has probably been mined. In both cases the tx is monitored
+// to get its receipt instead of setting it as invalid
+func (s *Sender) isAlreadySent(l2Tx *types.L2Transaction, sendErr error) bool {
+	if isAlreadyKnownError(sendErr) {
+		return true
+	}
+
+	return l2Tx.Status == types.TxStatusResend && isNonceTooLowError(sendErr)
+}
+
+// WaitWorkersAlive waits until at least one sender worker is alive, up to the Supervisor.StartupTimeout
+func (s *Sender) WaitWorkersAlive() error {
+	return s.workers.WaitAlive()
+}
+
+// WorkersStatus returns the status of the sender workers
+func (s *Sender) WorkersStatus() supervisor.Status {
+	return s.workers.Status()
+}
+
+// acquireL2Transaction marks the tx as owned by the sender. Returns an error if the tx is already being sent or the
+// sender is stopping
+func (s *Sender) acquireL2Transaction(id uint64) error {
+	s.inFlightCond.L.Lock()
+	defer s.inFlightCond.L.Unlock()
+
+	if s.stopped {
+		return ErrSenderStopped
+	}
+	if _, found := s.inFlight[id]; found {
+		return ErrAlreadyInFlight
+	}
+	s.inFlight[id] = struct{}{}
+	return nil
+}
+
+// releaseL2Transaction removes the ownership of the tx by the sender
+func (s *Sender) releaseL2Transaction(id uint64) {
+	s.inFlightCond.L.Lock()
+	defer s.inFlightCond.L.Unlock()
+
+	delete(s.inFlight, id)
+	s.inFlightCond.Broadcast()
+}
+
+// isL2TransactionInFlight returns true if the tx is currently being sent
+func (s *Sender) isL2TransactionInFlight(id uint64) bool {
+	s.inFlightCond.L.Lock()
+	defer s.inFlightCond.L.Unlock()
+
+	_, found := s.inFlight[id]
+	return found
+}
+
+// recordSendAttempt stores in the pool db the result of the attempt to send the tx to the sequencer
+func (s *Sender) recordSendAttempt(l2Tx *types.L2Transaction, sendErr error) {
+	now := time.Now()
+	errorMsg := ""
+	if sendErr != nil {
+		errorMsg = sendErr.Error()
+	}
+
+	err := s.poolDB.UpdateL2TransactionSendAttempt(context.Background(), l2Tx.Id, now, errorMsg)
+	if err != nil {
+		log.Errorf("error updating tx %s send attempt in the pool db, error: %v", l2Tx.Tag(), err)
+		return
+	}
+
+	l2Tx.AttemptCount++
+	if l2Tx.FirstSentAt.IsZero() {
+		l2Tx.FirstSentAt = now
+	}
+	l2Tx.LastSentAt = now
+	l2Tx.LastError = errorMsg
+}
+
+// isSendLimitReached returns true if the tx has reached the max number of send attempts or the max resend time
+func (s *Sender) isSendLimitReached(l2Tx *types.L2Transaction) (bool, string) {
+	if s.cfg.MaxSendAttempts > 0 && l2Tx.AttemptCount >= s.cfg.MaxSendAttempts {
+		return true, fmt.Sprintf("max send attempts reached (%d)", l2Tx.AttemptCount)
+	}
+	if s.cfg.MaxResendTime.Duration > 0 && !l2Tx.FirstSentAt.IsZero() && time.Since(l2Tx.FirstSentAt) > s.cfg.MaxResendTime.Duration {
+		return true, fmt.Sprintf("max resend time reached (first sent at %v)", l2Tx.FirstSentAt)
+	}
+	return false, ""
 }
 
 func (s *Sender) enqueueSenderRequest(request *sendRequest) {
+	if s.fairQueue != nil {
+		log.Debugf("send request for tx %s added to the fair queue", request.l2Tx.Tag())
+		s.fairQueue.push(request)
+		return
+	}
+
 	log.Debugf("send request for tx %s added to the queue channel", request.l2Tx.Tag())
 	// Enqueue monitorRequest in the channel. We do in a go func to avoid blocking in case the channel buffer is full
 	go func() { s.requestChan <- request }()
 }
 
-func (s *Sender) startSenderWorker(workerNum int) {
-	seqClient, err := ethclient.Dial(s.cfg.SequencerURL)
+// dispatchFairQueue moves the send requests from the fair queue to the queue channel. As the channel blocks when it's full,
+// the pending requests are kept in the fair queue where they are picked in round-robin across clients
+func (s *Sender) dispatchFairQueue(ctx context.Context) {
+	for {
+		request := s.fairQueue.pop()
+		if request == nil {
+			// fair queue has been closed
+			return
+		}
+
+		select {
+		case s.requestChan <- request:
+		case <-ctx.Done():
+			return
+		}
+	}
+}
 
+// runSenderWorker runs a sender worker. It's run by the workers supervisor, that restarts it if it returns an error
+func (s *Sender) runSenderWorker(ctx context.Context, workerNum int, ready func()) error {
+	dialCtx, cancel := context.WithTimeout(ctx, s.cfg.RPCReadTimeout.Duration)
+	defer cancel()
+
+	seqClient, err := s.dialSequencer(dialCtx, s.cfg.SequencerURL, s.cfg.SequencerAuth)
 	if err != nil {
-		log.Errorf("sender-worker[%03d]: error creating sequencer client for %s, err: %v", workerNum, s.cfg.SequencerURL, err)
-		return
+		return fmt.Errorf("error creating sequencer client for %s, err: %v", s.cfg.SequencerURL, err)
+	}
+	defer seqClient.Close()
+
+	// Check the sequencer is reachable before start processing requests
+	err = seqClient.Health(dialCtx)
+	if err != nil {
+		return fmt.Errorf("error connecting to sequencer %s, err: %v", s.cfg.SequencerURL, err)
+	}
+	chainID, err := seqClient.ChainID(dialCtx)
+	if err != nil {
+		return fmt.Errorf("error getting chain id from sequencer %s, err: %v", s.cfg.SequencerURL, err)
 	}
 
-	log.Debugf("sender-worker[%03d]: started", workerNum)
-	for sendRequest := range s.requestChan {
-		err := s.workerProcessRequest(sendRequest, seqClient, workerNum)
+	log.Debugf("sender-worker[%03d]: started, chain id: %d", workerNum, chainID)
+	ready()
+
+	for {
+		// The limiter is acquired before taking the requests from the queue, so while the calls are throttled the
+		// requests wait in the fair queue, where they are still picked across clients, and the worker can be stopped
+		if err := s.limiter.Acquire(ctx); err != nil {
+			log.Debugf("sender-worker[%03d]: stopped", workerNum)
+			return nil
+		}
+
+		var request *sendRequest
+		select {
+		case request = <-s.requestChan:
+		case <-ctx.Done():
+			s.limiter.Cancel()
+			log.Debugf("sender-worker[%03d]: stopped", workerNum)
+			return nil
+		}
 
-		sendRequest.err = err
-		sendRequest.wg.Done()
+		batch := []*sendRequest{request}
+		if s.cfg.BatchSend.Enabled {
+			batch = s.collectBatch(request)
+		}
+
+		sentAt := time.Now()
+		errs, panicErr := s.workerProcessRequests(batch, seqClient, workerNum)
+		latency := time.Since(sentAt)
+		s.limiter.Release(latency, firstError(errs))
+		for i, batchRequest := range batch {
+			batchRequest.err = errs[i]
+			batchRequest.sentAt = sentAt
+			batchRequest.latency = latency
+			batchRequest.wg.Done()
+		}
+
+		if panicErr != nil {
+			return panicErr
+		}
 	}
 }
 
-func (s *Sender) workerProcessRequest(request *sendRequest, seqClient *ethclient.Client, workerNum int) error {
+// workerProcessRequests sends the txs of the requests to the sequencer and returns the result for each request. If the
+// processing panics, all the requests are returned with error and the panic is returned as error to restart the worker
+func (s *Sender) workerProcessRequests(batch []*sendRequest, seqClient rpcclient.SequencerClient, workerNum int) (errs []error, panicErr error) {
+	defer func() {
+		if r := recover(); r != nil {
+			panicErr = fmt.Errorf("panic processing send requests: %v\n%s", r, debug.Stack())
+			errs = make([]error, len(batch))
+			for i := range errs {
+				errs[i] = fmt.Errorf("internal error sending tx")
+			}
+		}
+	}()
+
+	if s.cfg.BatchSend.Enabled {
+		return s.workerProcessBatch(batch, seqClient, workerNum), nil
+	}
+
+	return []error{s.workerProcessRequest(batch[0], seqClient, workerNum)}, nil
+}
+
+func (s *Sender) workerProcessRequest(request *sendRequest, seqClient rpcclient.SequencerClient, workerNum int) error {
 	log.Debugf("sender-worker[%03d]: sending tx %s", workerNum, request.l2Tx.Tag())
 
 	ctx, cancel := context.WithTimeout(context.Background(), s.cfg.RPCReadTimeout.Duration)
 	defer cancel()
-	return seqClient.Client().CallContext(ctx, nil, "eth_sendRawTransaction", request.l2Tx.Encoded)
+	return seqClient.SendRawTransaction(ctx, request.l2Tx.Encoded)
 }
 
-func (s *Sender) checkL2TransactionsToResend() {
-	for {
-		txs, err := s.poolDB.GetL2TransactionsToResend(context.Background())
+// firstError returns the first not nil error
+func firstError(errs []error) error {
+	for _, err := range errs {
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// collectBatch coalesces the queued send requests into a batch, until BatchSend.MaxSize requests are collected,
+// BatchSend.MaxLinger time elapses or the calls to the sequencer are throttled. The limiter token of each request is
+// taken before taking the request from the queue, so the token taken when the linger elapses is not used
+func (s *Sender) collectBatch(first *sendRequest) []*sendRequest {
+	batch := []*sendRequest{first}
+
+	linger := time.NewTimer(s.cfg.BatchSend.MaxLinger.Duration)
+	defer linger.Stop()
+
+	for len(batch) < int(s.cfg.BatchSend.MaxSize) {
+		if !s.limiter.TryAcquireToken() {
+			return batch
+		}
+
+		select {
+		case request, ok := <-s.requestChan:
+			if !ok {
+				return batch
+			}
+			batch = append(batch, request)
+		case <-linger.C:
+			return batch
+		}
+	}
+
+	return batch
+}
+
+func (s *Sender) workerProcessBatch(batch []*sendRequest, seqClient rpcclient.SequencerClient, workerNum int) []error {
+	log.Debugf("sender-worker[%03d]: sending batch of %d txs", workerNum, len(batch))
+
+	encoded := make([]string, len(batch))
+	for i, request := range batch {
+		encoded[i] = request.l2Tx.Encoded
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), s.cfg.RPCReadTimeout.Duration)
+	defer cancel()
+	errs, err := seqClient.SendRawTransactions(ctx, encoded)
+	if err != nil {
+		log.Errorf("sender-worker[%03d]: error sending batch of %d txs, error: %v", workerNum, len(batch), err)
+
+		// The whole batch request failed, all the send requests get the same error. It's not an error of the txs, so they
+		// are resent instead of being set as invalid
+		errs = make([]error, len(batch))
+		for i := range errs {
+			errs[i] = fmt.Errorf("%w: %v", ErrBatchRequestFailed, err)
+		}
+	}
+
+	return errs
+}
+
+func (s *Sender) checkL2TransactionsToResend(ctx context.Context) {
+	for ctx.Err() == nil {
+		// Only the leader instance resends the txs
+		if !s.leader.IsLeader() {
+			s.waitResendCheck(ctx)
+			continue
+		}
+
+		txs, err := s.poolDB.GetL2TransactionsToResend(ctx)
 		if err != nil && err != pgx.ErrNoRows {
 			log.Errorf("error loading txs to resend from pool, error: %v", err)
-			time.Sleep(s.cfg.ResendTxsCheckInterval.Duration)
+			s.waitResendCheck(ctx)
 			continue
 		}
 
@@ -116,6 +521,10 @@ func (s *Sender) checkL2TransactionsToResend() {
 		}
 
 		for _, l2Tx := range txs {
+			if ctx.Err() != nil {
+				return
+			}
+
 			err := s.SendL2Transaction(l2Tx)
 			if err != nil {
 				log.Infof("resending tx %s to sequencer returns error: %v", l2Tx.Tag(), err)
@@ -125,18 +534,97 @@ func (s *Sender) checkL2TransactionsToResend() {
 		}
 
 		if len(txs) == 0 {
-			time.Sleep(s.cfg.ResendTxsCheckInterval.Duration)
+			s.waitResendCheck(ctx)
+		}
+	}
+}
+
+// waitResendCheck waits ResendTxsCheckInterval time or until a tx changes to resend status
+func (s *Sender) waitResendCheck(ctx context.Context) {
+	timer := time.NewTimer(s.cfg.ResendTxsCheckInterval.Duration)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+	case <-s.resendWakeChan:
+		log.Debugf("resend check woken up by a tx notification")
+	case <-timer.C:
+	}
+}
+
+// listenL2TransactionNotifications listens for the pool database notifications of txs, reconnecting if the listen fails
+func (s *Sender) listenL2TransactionNotifications(ctx context.Context) {
+	notifications := make(chan types.L2TransactionNotification, s.cfg.QueueSize)
+	defer close(notifications)
+	go s.processL2TransactionNotifications(notifications)
+
+	for {
+		log.Infof("listening for tx notifications from the pool database")
+		err := s.poolDB.ListenL2TransactionNotifications(ctx, notifications)
+		if ctx.Err() != nil {
+			return
+		}
+
+		log.Errorf("error listening for tx notifications from the pool database, retrying in %v, error: %v", s.cfg.ResendTxsCheckInterval.Duration, err)
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(s.cfg.ResendTxsCheckInterval.Duration):
+		}
+	}
+}
+
+func (s *Sender) processL2TransactionNotifications(notifications <-chan types.L2TransactionNotification) {
+	for notification := range notifications {
+		switch notification.Status {
+		case types.TxStatusResend:
+			log.Debugf("tx [%d] changed to resend status", notification.Id)
+			select {
+			case s.resendWakeChan <- struct{}{}:
+			default:
+			}
+		case types.TxStatusPending:
+			// Only the pending txs inserted without owner (i.e. by an external process writing in the pool db) are sent
+			// from the notifications, and only if leases are enabled, as without leases we can't know if a pending tx is
+			// being sent by other instance. The txs inserted by the instances are owned by them and they are sent by the
+			// server request that inserted them
+			if s.poolDB.OwnerID() == "" || notification.OwnerID != "" || s.isL2TransactionInFlight(notification.Id) {
+				continue
+			}
+			go s.sendL2TransactionByID(notification.Id)
+		}
+	}
+}
+
+// sendL2TransactionByID sends the pending tx with the id if it's not owned by other instance
+func (s *Sender) sendL2TransactionByID(id uint64) {
+	l2Txs, err := s.poolDB.GetL2TransactionsToSendByID(context.Background(), id)
+	if err != nil {
+		log.Errorf("error loading pending tx [%d] from pool, error: %v", id, err)
+		return
+	}
+
+	for _, l2Tx := range l2Txs {
+		err := s.SendL2Transaction(l2Tx)
+		if err != nil {
+			log.Infof("sending notified tx %s to sequencer returns error: %v", l2Tx.Tag(), err)
+		} else {
+			log.Infof("notified tx %s sent to sequencer", l2Tx.Tag())
 		}
 	}
 }
 
-func (s *Sender) sendL2TransactionsFromPoolDB() {
-	l2Txs, err := s.poolDB.GetL2TransactionsToSend(context.Background())
+func (s *Sender) sendL2TransactionsFromPoolDB(ctx context.Context) {
+	l2Txs, err := s.poolDB.GetL2TransactionsToSend(ctx)
 	if err != nil {
 		log.Errorf("error when getting txs to send from the pool database, error: %v", err)
 	}
 
 	for _, l2Tx := range l2Txs {
+		if ctx.Err() != nil {
+			return
+		}
+
 		err := s.SendL2Transaction(l2Tx)
 		if err != nil {
 			log.Infof("sending tx %s to sequencer returns error: %v", l2Tx.Tag(), err)
@@ -145,3 +633,58 @@ func (s *Sender) sendL2TransactionsFromPoolDB() {
 		}
 	}
 }
+
+// checkPendingL2Transactions periodically looks for pending txs that have been stuck in the pool db (i.e. the process
+// crashed before sending them or the status update failed) and sends them again
+func (s *Sender) checkPendingL2Transactions(ctx context.Context) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(s.cfg.PendingTxsCheckInterval.Duration):
+		}
+
+		receivedBefore := time.Now().Add(-s.cfg.PendingTxsMinAge.Duration)
+		l2Txs, err := s.poolDB.GetL2TransactionsToRecover(ctx, receivedBefore)
+		if err != nil && err != pgx.ErrNoRows {
+			log.Errorf("error loading pending txs to recover from pool, error: %v", err)
+			continue
+		}
+
+		for _, l2Tx := range l2Txs {
+			if ctx.Err() != nil {
+				return
+			}
+
+			s.recoverL2Transaction(l2Tx, receivedBefore)
+		}
+	}
+}
+
+// recoverL2Transaction sends the stuck pending tx. As the tx may have been sent since the pending txs were loaded, it's
+// acquired first and then claimed in the pool db, that checks it's still pending and it has not been updated since the
+// updatedBefore time, so it's not sent twice
+func (s *Sender) recoverL2Transaction(l2Tx *types.L2Transaction, updatedBefore time.Time) {
+	if err := s.acquireL2Transaction(l2Tx.Id); err != nil {
+		log.Debugf("pending tx %s is not recovered, error: %v", l2Tx.Tag(), err)
+		return
+	}
+	defer s.releaseL2Transaction(l2Tx.Id)
+
+	claimed, err := s.poolDB.ClaimL2TransactionToRecover(context.Background(), l2Tx.Id, updatedBefore)
+	if errors.Is(err, pgx.ErrNoRows) {
+		log.Debugf("pending tx %s has already been sent", l2Tx.Tag())
+		return
+	} else if err != nil {
+		log.Errorf("error claiming pending tx %s to recover, error: %v", l2Tx.Tag(), err)
+		return
+	}
+
+	log.Infof("recovering pending tx %s received at %v", claimed.Tag(), claimed.ReceivedAt)
+	err = s.sendL2Transaction(claimed)
+	if err != nil {
+		log.Infof("sending recovered tx %s to sequencer returns error: %v", claimed.Tag(), err)
+	} else {
+		log.Infof("recovered tx %s sent to sequencer", claimed.Tag())
+	}
+}
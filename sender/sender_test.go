@@ -0,0 +1,504 @@
+package sender
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/0xPolygonHermez/zkevm-pool-manager/config/types"
+	"github.com/0xPolygonHermez/zkevm-pool-manager/limiter"
+	"github.com/0xPolygonHermez/zkevm-pool-manager/rpcclient"
+	"github.com/0xPolygonHermez/zkevm-pool-manager/supervisor"
+	poolTypes "github.com/0xPolygonHermez/zkevm-pool-manager/types"
+	"github.com/jackc/pgx/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakePoolDB is an in-memory pool database that records the status and send attempts of the txs
+type fakePoolDB struct {
+	statuses map[uint64]string
+	errors   map[uint64]string
+	attempts map[uint64]int
+	// toResend holds the txs returned by the next call to GetL2TransactionsToResend
+	toResend []*poolTypes.L2Transaction
+	// toRecover holds the txs returned by GetL2TransactionsToRecover, without checking their current status as it's a
+	// snapshot of the pool db
+	toRecover []*poolTypes.L2Transaction
+	// toSend holds the pending txs returned by GetL2TransactionsToSendByID
+	toSend map[uint64]*poolTypes.L2Transaction
+	// notifications are sent by ListenL2TransactionNotifications
+	notifications []poolTypes.L2TransactionNotification
+	ownerID       string
+	shadowResults []*poolTypes.ShadowSendResult
+	mutex         sync.Mutex
+}
+
+func newFakePoolDB() *fakePoolDB {
+	return &fakePoolDB{
+		statuses: make(map[uint64]string),
+		errors:   make(map[uint64]string),
+		attempts: make(map[uint64]int),
+		toSend:   make(map[uint64]*poolTypes.L2Transaction),
+	}
+}
+
+func (p *fakePoolDB) UpdateL2TransactionStatus(ctx context.Context, id uint64, newStatus string, errorMsg string) error {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	p.statuses[id] = newStatus
+	p.errors[id] = errorMsg
+	return nil
+}
+
+func (p *fakePoolDB) UpdateL2TransactionSendAttempt(ctx context.Context, id uint64, sentAt time.Time, errorMsg string) error {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	p.attempts[id]++
+	return nil
+}
+
+func (p *fakePoolDB) status(id uint64) (string, string, int) {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	return p.statuses[id], p.errors[id], p.attempts[id]
+}
+
+func (p *fakePoolDB) GetL2TransactionsToResend(ctx context.Context) ([]*poolTypes.L2Transaction, error) {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	txs := p.toResend
+	p.toResend = nil
+	return txs, nil
+}
+
+func (p *fakePoolDB) setToResend(txs ...*poolTypes.L2Transaction) {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	p.toResend = txs
+}
+
+func (p *fakePoolDB) GetL2TransactionsToSend(ctx context.Context) ([]*poolTypes.L2Transaction, error) {
+	return nil, nil
+}
+
+func (p *fakePoolDB) GetL2TransactionsToRecover(ctx context.Context, receivedBefore time.Time) ([]*poolTypes.L2Transaction, error) {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	return p.toRecover, nil
+}
+
+func (p *fakePoolDB) ClaimL2TransactionToRecover(ctx context.Context, id uint64, updatedBefore time.Time) (*poolTypes.L2Transaction, error) {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	if status, found := p.statuses[id]; found && status != poolTypes.TxStatusPending {
+		return nil, pgx.ErrNoRows
+	}
+	for _, l2Tx := range p.toRecover {
+		if l2Tx.Id == id {
+			claimed := *l2Tx
+			return &claimed, nil
+		}
+	}
+	return nil, pgx.ErrNoRows
+}
+
+func (p *fakePoolDB) GetL2TransactionsToSendByID(ctx context.Context, id uint64) ([]*poolTypes.L2Transaction, error) {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	if l2Tx, found := p.toSend[id]; found {
+		return []*poolTypes.L2Transaction{l2Tx}, nil
+	}
+	return nil, nil
+}
+
+func (p *fakePoolDB) ListenL2TransactionNotifications(ctx context.Context, notifications chan<- poolTypes.L2TransactionNotification) error {
+	for _, notification := range p.notifications {
+		select {
+		case notifications <- notification:
+		case <-ctx.Done():
+			return nil
+		}
+	}
+	<-ctx.Done()
+	return nil
+}
+
+func (p *fakePoolDB) OwnerID() string {
+	return p.ownerID
+}
+
+func (p *fakePoolDB) AddShadowSendResult(ctx context.Context, result *poolTypes.ShadowSendResult) error {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	p.shadowResults = append(p.shadowResults, result)
+	return nil
+}
+
+// shadowResult returns the shadow send result stored for the tx, or nil if there is none
+func (p *fakePoolDB) shadowResult(id uint64) *poolTypes.ShadowSendResult {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	for _, result := range p.shadowResults {
+		if result.TxId == id {
+			return result
+		}
+	}
+	return nil
+}
+
+// fakeMonitor records the txs added to be monitored
+type fakeMonitor struct {
+	monitored []uint64
+	mutex     sync.Mutex
+}
+
+func (m *fakeMonitor) AddL2Transaction(l2Tx *poolTypes.L2Transaction) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	m.monitored = append(m.monitored, l2Tx.Id)
+}
+
+var testSupervisorConfig = supervisor.Config{
+	RestartInitialBackoff: types.NewDuration(10 * time.Millisecond),
+	RestartMaxBackoff:     types.NewDuration(10 * time.Millisecond),
+	StartupTimeout:        types.NewDuration(500 * time.Millisecond),
+}
+
+type fakeLeader struct{}
+
+func (l *fakeLeader) IsLeader() bool {
+	return true
+}
+
+func (l *fakeLeader) OnAcquired(fn func()) {
+	fn()
+}
+
+func newTestSender(t *testing.T, cfg Config, seqClient *rpcclient.FakeSequencerClient) (*Sender, *fakePoolDB, *fakeMonitor) {
+	return newTestSenderWithPoolDB(t, cfg, seqClient, newFakePoolDB())
+}
+
+func newTestSenderWithPoolDB(t *testing.T, cfg Config, seqClient *rpcclient.FakeSequencerClient, poolDB *fakePoolDB) (*Sender, *fakePoolDB, *fakeMonitor) {
+	monitor := &fakeMonitor{}
+
+	cfg.Workers = 2
+	cfg.QueueSize = 10
+	cfg.RPCReadTimeout = types.NewDuration(time.Second)
+	cfg.ResendTxsCheckInterval = types.NewDuration(time.Minute)
+	cfg.Supervisor = testSupervisorConfig
+
+	s := NewSender(cfg, poolDB, monitor, &fakeLeader{})
+	s.dialSequencer = func(ctx context.Context, url string, cfg rpcclient.Config) (rpcclient.SequencerClient, error) {
+		return seqClient, nil
+	}
+	if s.shadow != nil {
+		s.shadow.dialSequencer = s.dialSequencer
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+	s.Start(ctx)
+
+	return s, poolDB, monitor
+}
+
+func TestSendL2TransactionSent(t *testing.T) {
+	seqClient := rpcclient.NewFakeSequencerClient(1001)
+	s, poolDB, monitor := newTestSender(t, Config{}, seqClient)
+	require.NoError(t, s.WaitWorkersAlive())
+
+	l2Tx := &poolTypes.L2Transaction{Id: 1, Hash: "0x01", Encoded: "0xaa"}
+	require.NoError(t, s.SendL2Transaction(l2Tx))
+
+	status, errorMsg, attempts := poolDB.status(1)
+	assert.Equal(t, poolTypes.TxStatusSent, status)
+	assert.Empty(t, errorMsg)
+	assert.Equal(t, 1, attempts)
+	assert.Equal(t, uint64(1), l2Tx.AttemptCount)
+	assert.Equal(t, []string{"0xaa"}, seqClient.Sent())
+	assert.Equal(t, []uint64{1}, monitor.monitored)
+}
+
+func TestSendL2TransactionInvalid(t *testing.T) {
+	seqClient := rpcclient.NewFakeSequencerClient(1001)
+	seqClient.SetSendError("0xbb", errors.New("insufficient funds"))
+	s, poolDB, monitor := newTestSender(t, Config{}, seqClient)
+	require.NoError(t, s.WaitWorkersAlive())
+
+	l2Tx := &poolTypes.L2Transaction{Id: 2, Hash: "0x02", Encoded: "0xbb"}
+	require.EqualError(t, s.SendL2Transaction(l2Tx), "insufficient funds")
+
+	status, errorMsg, attempts := poolDB.status(2)
+	assert.Equal(t, poolTypes.TxStatusInvalid, status)
+	assert.Equal(t, "insufficient funds", errorMsg)
+	assert.Equal(t, 1, attempts)
+	assert.Equal(t, "insufficient funds", l2Tx.LastError)
+	assert.Empty(t, seqClient.Sent())
+	assert.Empty(t, monitor.monitored)
+}
+
+func TestSendL2TransactionBatch(t *testing.T) {
+	seqClient := rpcclient.NewFakeSequencerClient(1001)
+	seqClient.SetSendError("0x02", errors.New("nonce too high"))
+	cfg := Config{BatchSend: BatchSendConfig{Enabled: true, MaxSize: 3, MaxLinger: types.NewDuration(50 * time.Millisecond)}}
+	s, poolDB, monitor := newTestSender(t, cfg, seqClient)
+	require.NoError(t, s.WaitWorkersAlive())
+
+	var wg sync.WaitGroup
+	for id := uint64(1); id <= 3; id++ {
+		wg.Add(1)
+		go func(id uint64) {
+			defer wg.Done()
+			encoded := []string{"", "0x01", "0x02", "0x03"}[id]
+			_ = s.SendL2Transaction(&poolTypes.L2Transaction{Id: id, Encoded: encoded})
+		}(id)
+	}
+	wg.Wait()
+
+	for id, expected := range map[uint64]string{1: poolTypes.TxStatusSent, 2: poolTypes.TxStatusInvalid, 3: poolTypes.TxStatusSent} {
+		status, _, _ := poolDB.status(id)
+		assert.Equal(t, expected, status, "tx %d", id)
+	}
+	assert.ElementsMatch(t, []string{"0x01", "0x03"}, seqClient.Sent())
+	assert.ElementsMatch(t, []uint64{1, 3}, monitor.monitored)
+}
+
+func TestSendL2TransactionStopped(t *testing.T) {
+	seqClient := rpcclient.NewFakeSequencerClient(1001)
+	s, poolDB, _ := newTestSender(t, Config{}, seqClient)
+	require.NoError(t, s.WaitWorkersAlive())
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	require.NoError(t, s.Stop(ctx))
+
+	err := s.SendL2Transaction(&poolTypes.L2Transaction{Id: 3, Encoded: "0xcc"})
+	assert.ErrorIs(t, err, ErrSenderStopped)
+
+	status, _, attempts := poolDB.status(3)
+	assert.Empty(t, status)
+	assert.Equal(t, 0, attempts)
+}
+
+func TestSendL2TransactionThrottled(t *testing.T) {
+	seqClient := rpcclient.NewFakeSequencerClient(1001)
+	s, _, _ := newTestSender(t, Config{RateLimit: limiter.Config{RequestsPerSecond: 0.001, Burst: 1}}, seqClient)
+	require.NoError(t, s.WaitWorkersAlive())
+
+	require.NoError(t, s.SendL2Transaction(&poolTypes.L2Transaction{Id: 1, Encoded: "0xaa"}))
+
+	// While the calls are throttled the request is kept in the queue
+	go func() { _ = s.SendL2Transaction(&poolTypes.L2Transaction{Id: 2, Encoded: "0xbb"}) }()
+	require.Eventually(t, func() bool { return len(s.requestChan) == 1 }, time.Second, 10*time.Millisecond)
+	time.Sleep(50 * time.Millisecond)
+	assert.Len(t, s.requestChan, 1)
+	assert.Equal(t, []string{"0xaa"}, seqClient.Sent())
+
+	// The workers waiting for the limiter are stopped right away
+	s.stopWorkers()
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	require.NoError(t, s.workers.Wait(ctx))
+}
+
+func TestSenderSequencerDown(t *testing.T) {
+	seqClient := rpcclient.NewFakeSequencerClient(1001)
+	seqClient.SetHealthError(errors.New("connection refused"))
+	s, _, _ := newTestSender(t, Config{}, seqClient)
+
+	assert.Error(t, s.WaitWorkersAlive())
+
+	seqClient.SetHealthError(nil)
+	require.Eventually(t, func() bool { return s.WorkersStatus().Alive > 0 }, time.Second, 10*time.Millisecond)
+}
+
+func TestSendL2TransactionAlreadySent(t *testing.T) {
+	seqClient := rpcclient.NewFakeSequencerClient(1001)
+	seqClient.SetSendError("0x01", errors.New("already known"))
+	seqClient.SetSendError("0x02", errors.New("nonce too low"))
+	seqClient.SetSendError("0x03", errors.New("nonce too low"))
+	s, poolDB, monitor := newTestSender(t, Config{}, seqClient)
+	require.NoError(t, s.WaitWorkersAlive())
+
+	// The sequencer already has the tx
+	require.NoError(t, s.SendL2Transaction(&poolTypes.L2Transaction{Id: 1, Encoded: "0x01", Status: poolTypes.TxStatusPending}))
+	// The resent tx has already been mined
+	require.NoError(t, s.SendL2Transaction(&poolTypes.L2Transaction{Id: 2, Encoded: "0x02", Status: poolTypes.TxStatusResend}))
+	// A new tx with a used nonce is invalid
+	require.Error(t, s.SendL2Transaction(&poolTypes.L2Transaction{Id: 3, Encoded: "0x03", Status: poolTypes.TxStatusPending}))
+
+	for id, expected := range map[uint64]string{1: poolTypes.TxStatusSent, 2: poolTypes.TxStatusSent, 3: poolTypes.TxStatusInvalid} {
+		status, _, _ := poolDB.status(id)
+		assert.Equal(t, expected, status, "tx %d", id)
+	}
+	assert.Equal(t, []uint64{1, 2}, monitor.monitored)
+}
+
+func TestSendL2TransactionBatchTransportError(t *testing.T) {
+	seqClient := rpcclient.NewFakeSequencerClient(1001)
+	seqClient.SetBatchError(errors.New("connection reset by peer"))
+	cfg := Config{BatchSend: BatchSendConfig{Enabled: true, MaxSize: 3, MaxLinger: types.NewDuration(50 * time.Millisecond)}}
+	s, poolDB, monitor := newTestSender(t, cfg, seqClient)
+	require.NoError(t, s.WaitWorkersAlive())
+
+	var wg sync.WaitGroup
+	for id := uint64(1); id <= 3; id++ {
+		wg.Add(1)
+		go func(id uint64) {
+			defer wg.Done()
+			err := s.SendL2Transaction(&poolTypes.L2Transaction{Id: id, Encoded: []string{"", "0x01", "0x02", "0x03"}[id]})
+			assert.ErrorIs(t, err, ErrBatchRequestFailed)
+		}(id)
+	}
+	wg.Wait()
+
+	// The txs are not invalid, they are left to be resent
+	for id := uint64(1); id <= 3; id++ {
+		status, errorMsg, attempts := poolDB.status(id)
+		assert.Equal(t, poolTypes.TxStatusResend, status, "tx %d", id)
+		assert.Contains(t, errorMsg, "connection reset by peer")
+		assert.Equal(t, 1, attempts)
+	}
+	assert.Empty(t, seqClient.Sent())
+	assert.Empty(t, monitor.monitored)
+}
+
+func TestSendL2TransactionAttempts(t *testing.T) {
+	seqClient := rpcclient.NewFakeSequencerClient(1001)
+	seqClient.SetSendError("0x01", errors.New("internal error"))
+	s, poolDB, _ := newTestSender(t, Config{MaxSendAttempts: 2}, seqClient)
+	require.NoError(t, s.WaitWorkersAlive())
+
+	l2Tx := &poolTypes.L2Transaction{Id: 1, Encoded: "0x01", Status: poolTypes.TxStatusResend}
+	require.Error(t, s.SendL2Transaction(l2Tx))
+	firstSentAt := l2Tx.FirstSentAt
+	require.Error(t, s.SendL2Transaction(l2Tx))
+
+	_, _, attempts := poolDB.status(1)
+	assert.Equal(t, 2, attempts)
+	assert.Equal(t, uint64(2), l2Tx.AttemptCount)
+	assert.Equal(t, firstSentAt, l2Tx.FirstSentAt)
+	assert.True(t, l2Tx.LastSentAt.After(firstSentAt))
+	assert.Equal(t, "internal error", l2Tx.LastError)
+
+	// The limit is reached, the tx is dropped without sending it
+	seqClient.SetSendError("0x01", nil)
+	assert.ErrorIs(t, s.SendL2Transaction(l2Tx), ErrSendLimitReached)
+
+	status, errorMsg, attempts := poolDB.status(1)
+	assert.Equal(t, poolTypes.TxStatusDropped, status)
+	assert.Equal(t, "max send attempts reached (2)", errorMsg)
+	assert.Equal(t, 2, attempts)
+	assert.Empty(t, seqClient.Sent())
+}
+
+func TestSendL2TransactionSendLimit(t *testing.T) {
+	seqClient := rpcclient.NewFakeSequencerClient(1001)
+	cfg := Config{MaxSendAttempts: 3, MaxResendTime: types.NewDuration(time.Hour)}
+	s, poolDB, monitor := newTestSender(t, cfg, seqClient)
+	require.NoError(t, s.WaitWorkersAlive())
+
+	// Pending txs read from the pool db are checked too, not only the resent ones
+	assert.ErrorIs(t, s.SendL2Transaction(&poolTypes.L2Transaction{Id: 1, Encoded: "0x01", Status: poolTypes.TxStatusPending, AttemptCount: 3}), ErrSendLimitReached)
+
+	// The resend loop drops the txs that reached the max attempts or the max resend time, and resends the rest
+	poolDB.setToResend(
+		&poolTypes.L2Transaction{Id: 2, Encoded: "0x02", Status: poolTypes.TxStatusResend, AttemptCount: 3, FirstSentAt: time.Now()},
+		&poolTypes.L2Transaction{Id: 3, Encoded: "0x03", Status: poolTypes.TxStatusResend, AttemptCount: 1, FirstSentAt: time.Now().Add(-2 * time.Hour)},
+		&poolTypes.L2Transaction{Id: 4, Encoded: "0x04", Status: poolTypes.TxStatusResend, AttemptCount: 2, FirstSentAt: time.Now()},
+	)
+	s.resendWakeChan <- struct{}{}
+
+	require.Eventually(t, func() bool {
+		status, _, _ := poolDB.status(4)
+		return status == poolTypes.TxStatusSent
+	}, time.Second, 10*time.Millisecond)
+
+	for id, expected := range map[uint64]string{1: poolTypes.TxStatusDropped, 2: poolTypes.TxStatusDropped, 3: poolTypes.TxStatusDropped, 4: poolTypes.TxStatusSent} {
+		status, _, _ := poolDB.status(id)
+		assert.Equal(t, expected, status, "tx %d", id)
+	}
+	assert.Equal(t, []string{"0x04"}, seqClient.Sent())
+	assert.Equal(t, []uint64{4}, monitor.monitored)
+}
+
+func TestCheckPendingL2Transactions(t *testing.T) {
+	seqClient := rpcclient.NewFakeSequencerClient(1001)
+	cfg := Config{PendingTxsCheckInterval: types.NewDuration(10 * time.Millisecond)}
+	s, poolDB, monitor := newTestSender(t, cfg, seqClient)
+	require.NoError(t, s.WaitWorkersAlive())
+
+	// The tx 1 is sent by the server request after the sweep loads the pending txs, so it must not be sent again
+	require.NoError(t, s.SendL2Transaction(&poolTypes.L2Transaction{Id: 1, Encoded: "0x01", Status: poolTypes.TxStatusPending}))
+	poolDB.mutex.Lock()
+	poolDB.toRecover = []*poolTypes.L2Transaction{
+		{Id: 1, Encoded: "0x01", Status: poolTypes.TxStatusPending},
+		{Id: 2, Encoded: "0x02", Status: poolTypes.TxStatusPending},
+	}
+	poolDB.mutex.Unlock()
+
+	require.Eventually(t, func() bool {
+		status, _, _ := poolDB.status(2)
+		return status == poolTypes.TxStatusSent
+	}, time.Second, 10*time.Millisecond)
+
+	// Wait some sweeps more to check the txs are sent once
+	time.Sleep(50 * time.Millisecond)
+	assert.Equal(t, []string{"0x01", "0x02"}, seqClient.Sent())
+	_, _, attempts := poolDB.status(1)
+	assert.Equal(t, 1, attempts)
+	monitor.mutex.Lock()
+	assert.Equal(t, []uint64{1, 2}, monitor.monitored)
+	monitor.mutex.Unlock()
+}
+
+func TestProcessL2TransactionNotifications(t *testing.T) {
+	seqClient := rpcclient.NewFakeSequencerClient(1001)
+	poolDB := newFakePoolDB()
+	poolDB.ownerID = "instance-a"
+	for id := uint64(1); id <= 3; id++ {
+		poolDB.toSend[id] = &poolTypes.L2Transaction{Id: id, Encoded: []string{"", "0x01", "0x02", "0x03"}[id], Status: poolTypes.TxStatusPending}
+	}
+	poolDB.toResend = []*poolTypes.L2Transaction{{Id: 4, Encoded: "0x04", Status: poolTypes.TxStatusResend}}
+	poolDB.notifications = []poolTypes.L2TransactionNotification{
+		// Inserted by an external process, without owner
+		{Id: 1, Status: poolTypes.TxStatusPending},
+		// Inserted by other instance, that sends it
+		{Id: 2, Status: poolTypes.TxStatusPending, OwnerID: "instance-b"},
+		// Inserted by this instance, sent by the server request
+		{Id: 3, Status: poolTypes.TxStatusPending, OwnerID: "instance-a"},
+		// Changed to resend, it wakes up the resend loop
+		{Id: 4, Status: poolTypes.TxStatusResend},
+	}
+	cfg := Config{NotificationsEnabled: true}
+	s, _, monitor := newTestSenderWithPoolDB(t, cfg, seqClient, poolDB)
+	require.NoError(t, s.WaitWorkersAlive())
+
+	require.Eventually(t, func() bool { return len(seqClient.Sent()) == 2 }, time.Second, 10*time.Millisecond)
+	time.Sleep(50 * time.Millisecond)
+
+	assert.ElementsMatch(t, []string{"0x01", "0x04"}, seqClient.Sent())
+	for id, expected := range map[uint64]string{1: poolTypes.TxStatusSent, 2: "", 3: "", 4: poolTypes.TxStatusSent} {
+		status, _, _ := poolDB.status(id)
+		assert.Equal(t, expected, status, "tx %d", id)
+	}
+	monitor.mutex.Lock()
+	assert.ElementsMatch(t, []uint64{1, 4}, monitor.monitored)
+	monitor.mutex.Unlock()
+}
+
+func TestProcessL2TransactionNotificationsWithoutLeases(t *testing.T) {
+	seqClient := rpcclient.NewFakeSequencerClient(1001)
+	poolDB := newFakePoolDB()
+	poolDB.toSend[1] = &poolTypes.L2Transaction{Id: 1, Encoded: "0x01", Status: poolTypes.TxStatusPending}
+	poolDB.notifications = []poolTypes.L2TransactionNotification{{Id: 1, Status: poolTypes.TxStatusPending}}
+	s, _, _ := newTestSenderWithPoolDB(t, Config{NotificationsEnabled: true}, seqClient, poolDB)
+	require.NoError(t, s.WaitWorkersAlive())
+
+	// Without leases the pending txs may be being sent by other instance, so they are not sent from the notifications
+	time.Sleep(50 * time.Millisecond)
+	assert.Empty(t, seqClient.Sent())
+}
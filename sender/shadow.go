@@ -0,0 +1,131 @@
+package sender
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/0xPolygonHermez/zkevm-pool-manager/log"
+	"github.com/0xPolygonHermez/zkevm-pool-manager/rpcclient"
+	"github.com/0xPolygonHermez/zkevm-pool-manager/supervisor"
+	"github.com/0xPolygonHermez/zkevm-pool-manager/types"
+)
+
+// shadowSender mirrors the txs sent to the sequencer to a shadow sequencer and records the results of both to compare
+// them. It's fire-and-forget: the shadow results never change the status of the txs
+type shadowSender struct {
+	cfg         ShadowConfig
+	poolDB      poolDBInterface
+	workers     *supervisor.Supervisor
+	requestChan chan *shadowRequest
+	// dialSequencer creates the clients used by the workers to send the txs to the shadow sequencer
+	dialSequencer dialSequencerFunc
+}
+
+type shadowRequest struct {
+	l2Tx           types.L2Transaction
+	sentAt         time.Time
+	primaryErr     error
+	primaryLatency time.Duration
+}
+
+// newShadowSender creates and init a shadowSender
+func newShadowSender(cfg ShadowConfig, supervisorCfg supervisor.Config, poolDB poolDBInterface, dialSequencer dialSequencerFunc) *shadowSender {
+	return &shadowSender{
+		cfg:           cfg,
+		poolDB:        poolDB,
+		workers:       supervisor.NewSupervisor("shadow-sender", supervisorCfg),
+		requestChan:   make(chan *shadowRequest, cfg.QueueSize),
+		dialSequencer: dialSequencer,
+	}
+}
+
+// start starts the shadow sender workers, that run until the context is done
+func (s *shadowSender) start(ctx context.Context) {
+	log.Infof("starting %d shadow sender workers for %s", s.cfg.Workers, s.cfg.SequencerURL)
+	for i := 0; i < int(s.cfg.Workers); i++ {
+		s.workers.Go(ctx, i, s.runShadowWorker)
+	}
+}
+
+// wait waits for the shadow sender workers to finish
+func (s *shadowSender) wait(ctx context.Context) error {
+	return s.workers.Wait(ctx)
+}
+
+// send enqueues the tx to be sent to the shadow sequencer with the result of sending it to the primary sequencer. It
+// never blocks, if the queue is full the tx is not mirrored
+func (s *shadowSender) send(request *sendRequest) {
+	shadowRequest := &shadowRequest{
+		l2Tx:           request.l2Tx,
+		sentAt:         request.sentAt,
+		primaryErr:     request.err,
+		primaryLatency: request.latency,
+	}
+
+	select {
+	case s.requestChan <- shadowRequest:
+	default:
+		log.Warnf("shadow sender queue is full, tx %s not sent to shadow sequencer", request.l2Tx.Tag())
+	}
+}
+
+// runShadowWorker runs a shadow sender worker. It's run by the workers supervisor, that restarts it if it returns an error
+func (s *shadowSender) runShadowWorker(ctx context.Context, workerNum int, ready func()) error {
+	dialCtx, cancel := context.WithTimeout(ctx, s.cfg.RPCReadTimeout.Duration)
+	defer cancel()
+
+	seqClient, err := s.dialSequencer(dialCtx, s.cfg.SequencerURL, s.cfg.SequencerAuth)
+	if err != nil {
+		return fmt.Errorf("error creating shadow sequencer client for %s, err: %v", s.cfg.SequencerURL, err)
+	}
+	defer seqClient.Close()
+
+	log.Debugf("shadow-sender-worker[%03d]: started", workerNum)
+	ready()
+
+	for {
+		select {
+		case request := <-s.requestChan:
+			s.workerProcessRequest(ctx, request, seqClient, workerNum)
+		case <-ctx.Done():
+			log.Debugf("shadow-sender-worker[%03d]: stopped", workerNum)
+			return nil
+		}
+	}
+}
+
+func (s *shadowSender) workerProcessRequest(ctx context.Context, request *shadowRequest, seqClient rpcclient.SequencerClient, workerNum int) {
+	log.Debugf("shadow-sender-worker[%03d]: sending tx %s", workerNum, request.l2Tx.Tag())
+
+	sendCtx, cancel := context.WithTimeout(ctx, s.cfg.RPCReadTimeout.Duration)
+	defer cancel()
+
+	start := time.Now()
+	err := seqClient.SendRawTransaction(sendCtx, request.l2Tx.Encoded)
+	latency := time.Since(start)
+
+	result := &types.ShadowSendResult{
+		TxId:           request.l2Tx.Id,
+		Hash:           request.l2Tx.Hash,
+		SentAt:         request.sentAt,
+		PrimaryLatency: request.primaryLatency,
+		ShadowURL:      s.cfg.SequencerURL,
+		ShadowLatency:  latency,
+	}
+	if request.primaryErr != nil {
+		result.PrimaryError = request.primaryErr.Error()
+	}
+	if err != nil {
+		result.ShadowError = err.Error()
+	}
+
+	if !result.Match() {
+		log.Warnf("shadow sequencer result mismatch for tx %s, primary error: %s, shadow error: %s", request.l2Tx.Tag(), result.PrimaryError, result.ShadowError)
+	}
+
+	err = s.poolDB.AddShadowSendResult(ctx, result)
+	if err != nil {
+		log.Errorf("error storing shadow send result for tx %s, error: %v", request.l2Tx.Tag(), err)
+	}
+}
@@ -0,0 +1,122 @@
+package sender
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/0xPolygonHermez/zkevm-pool-manager/config/types"
+	"github.com/0xPolygonHermez/zkevm-pool-manager/rpcclient"
+	poolTypes "github.com/0xPolygonHermez/zkevm-pool-manager/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestShadowSender(poolDB *fakePoolDB, shadowClient *rpcclient.FakeSequencerClient, queueSize uint16) *shadowSender {
+	cfg := ShadowConfig{
+		Enabled:        true,
+		SequencerURL:   "http://shadow-sequencer",
+		Workers:        1,
+		QueueSize:      queueSize,
+		RPCReadTimeout: types.NewDuration(time.Second),
+	}
+
+	return newShadowSender(cfg, testSupervisorConfig, poolDB, func(ctx context.Context, url string, cfg rpcclient.Config) (rpcclient.SequencerClient, error) {
+		return shadowClient, nil
+	})
+}
+
+func TestShadowSend(t *testing.T) {
+	poolDB := newFakePoolDB()
+	shadowClient := rpcclient.NewFakeSequencerClient(1001)
+	shadowClient.SetSendError("0x02", errors.New("nonce too low"))
+	s := newTestShadowSender(poolDB, shadowClient, 10)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	s.start(ctx)
+	require.NoError(t, s.workers.WaitAlive())
+
+	sentAt := time.Now()
+	s.send(&sendRequest{l2Tx: poolTypes.L2Transaction{Id: 1, Hash: "0x01", Encoded: "0x01"}, sentAt: sentAt, latency: 10 * time.Millisecond})
+	s.send(&sendRequest{l2Tx: poolTypes.L2Transaction{Id: 2, Hash: "0x02", Encoded: "0x02"}, sentAt: sentAt, latency: 20 * time.Millisecond})
+	s.send(&sendRequest{l2Tx: poolTypes.L2Transaction{Id: 3, Hash: "0x03", Encoded: "0x03"}, sentAt: sentAt, err: errors.New("insufficient funds")})
+
+	require.Eventually(t, func() bool { return poolDB.shadowResult(3) != nil }, time.Second, 10*time.Millisecond)
+
+	// Both sequencers accepted the tx
+	result := poolDB.shadowResult(1)
+	require.NotNil(t, result)
+	assert.Equal(t, "0x01", result.Hash)
+	assert.Equal(t, sentAt, result.SentAt)
+	assert.Equal(t, "http://shadow-sequencer", result.ShadowURL)
+	assert.Equal(t, 10*time.Millisecond, result.PrimaryLatency)
+	assert.True(t, result.Match())
+
+	// Only the shadow sequencer rejected the tx
+	result = poolDB.shadowResult(2)
+	require.NotNil(t, result)
+	assert.Empty(t, result.PrimaryError)
+	assert.Equal(t, "nonce too low", result.ShadowError)
+	assert.False(t, result.Match())
+
+	// Only the primary sequencer rejected the tx
+	result = poolDB.shadowResult(3)
+	require.NotNil(t, result)
+	assert.Equal(t, "insufficient funds", result.PrimaryError)
+	assert.Empty(t, result.ShadowError)
+	assert.False(t, result.Match())
+
+	// The shadow results never change the status of the txs
+	assert.ElementsMatch(t, []string{"0x01", "0x03"}, shadowClient.Sent())
+	assert.Empty(t, poolDB.statuses)
+}
+
+func TestShadowSendQueueFull(t *testing.T) {
+	poolDB := newFakePoolDB()
+	s := newTestShadowSender(poolDB, rpcclient.NewFakeSequencerClient(1001), 2)
+
+	// The workers are not started, so the queue gets full and the next txs are dropped without blocking
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for id := uint64(1); id <= 5; id++ {
+			s.send(&sendRequest{l2Tx: poolTypes.L2Transaction{Id: id}})
+		}
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		require.Fail(t, "shadow send blocked with the queue full")
+	}
+	require.Len(t, s.requestChan, 2)
+	assert.Equal(t, uint64(1), (<-s.requestChan).l2Tx.Id)
+	assert.Equal(t, uint64(2), (<-s.requestChan).l2Tx.Id)
+}
+
+func TestSendL2TransactionShadow(t *testing.T) {
+	seqClient := rpcclient.NewFakeSequencerClient(1001)
+	seqClient.SetSendError("0x02", errors.New("insufficient funds"))
+	cfg := Config{Shadow: ShadowConfig{Enabled: true, SequencerURL: "http://shadow-sequencer", Workers: 1, QueueSize: 10, RPCReadTimeout: types.NewDuration(time.Second)}}
+	s, poolDB, _ := newTestSender(t, cfg, seqClient)
+	require.NoError(t, s.WaitWorkersAlive())
+
+	require.NoError(t, s.SendL2Transaction(&poolTypes.L2Transaction{Id: 1, Hash: "0x01", Encoded: "0x01"}))
+	require.Error(t, s.SendL2Transaction(&poolTypes.L2Transaction{Id: 2, Hash: "0x02", Encoded: "0x02"}))
+
+	// The result of the primary send is persisted with the shadow result, that doesn't change the tx status
+	require.Eventually(t, func() bool { return poolDB.shadowResult(1) != nil && poolDB.shadowResult(2) != nil }, time.Second, 10*time.Millisecond)
+	result := poolDB.shadowResult(1)
+	assert.True(t, result.Match())
+	assert.False(t, result.SentAt.IsZero())
+	result = poolDB.shadowResult(2)
+	assert.Equal(t, "insufficient funds", result.PrimaryError)
+	assert.Equal(t, "insufficient funds", result.ShadowError)
+
+	for id, expected := range map[uint64]string{1: poolTypes.TxStatusSent, 2: poolTypes.TxStatusInvalid} {
+		status, _, _ := poolDB.status(id)
+		assert.Equal(t, expected, status, "tx %d", id)
+	}
+}
@@ -0,0 +1,89 @@
+package server
+
+import (
+	"context"
+	"errors"
+
+	"github.com/0xPolygonHermez/zkevm-pool-manager/log"
+	"github.com/0xPolygonHermez/zkevm-pool-manager/types"
+	"github.com/jackc/pgx/v4"
+)
+
+// AdminEndpoints contains implementations for the pool-manager admin JSON-RPC endpoints
+type AdminEndpoints struct {
+	poolDB adminPoolDBInterface
+}
+
+// NewAdminEndpoints creates an new instance of pool-manager admin JSON-RPC endpoints
+func NewAdminEndpoints(poolDB adminPoolDBInterface) *AdminEndpoints {
+	return &AdminEndpoints{poolDB: poolDB}
+}
+
+// ListDeadLetters returns the dead-lettered txs that match the filter
+func (e *AdminEndpoints) ListDeadLetters(filter *types.DeadLetterFilter) (interface{}, Error) {
+	deadLetters, err := e.poolDB.GetDeadLetters(context.Background(), deadLetterFilter(filter))
+	if err != nil {
+		log.Errorf("error getting dead-lettered txs, error: %v", err)
+		return nil, NewServerError(DefaultErrorCode, "error getting dead-lettered txs")
+	}
+
+	return deadLetters, nil
+}
+
+// GetDeadLetter returns the dead-lettered tx with its error history
+func (e *AdminEndpoints) GetDeadLetter(txID uint64) (interface{}, Error) {
+	deadLetter, err := e.poolDB.GetDeadLetter(context.Background(), txID)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, NewServerError(DefaultErrorCode, "tx %d is not dead-lettered", txID)
+	} else if err != nil {
+		log.Errorf("error getting dead-lettered tx %d, error: %v", txID, err)
+		return nil, NewServerError(DefaultErrorCode, "error getting dead-lettered tx")
+	}
+
+	return deadLetter, nil
+}
+
+// RequeueDeadLetters moves the dead-lettered txs that match the filter back to be resent. At least one filter is required
+// to avoid requeuing all the dead-lettered txs by mistake. The reverted txs are not requeued, as they have been mined.
+// Returns the number of requeued txs
+func (e *AdminEndpoints) RequeueDeadLetters(filter *types.DeadLetterFilter) (interface{}, Error) {
+	f := deadLetterFilter(filter)
+	if f.IsEmpty() {
+		return nil, NewServerError(InvalidParamsErrorCode, "at least one filter is required to requeue dead-lettered txs")
+	}
+
+	requeued, err := e.poolDB.RequeueDeadLetters(context.Background(), f)
+	if err != nil {
+		log.Errorf("error requeuing dead-lettered txs, error: %v", err)
+		return nil, NewServerError(DefaultErrorCode, "error requeuing dead-lettered txs")
+	}
+
+	log.Infof("%d dead-lettered txs requeued", requeued)
+	return requeued, nil
+}
+
+// PurgeDeadLetters deletes the dead-lettered txs that match the filter. At least one filter is required to avoid purging
+// all the dead-lettered txs by mistake. Returns the number of purged txs
+func (e *AdminEndpoints) PurgeDeadLetters(filter *types.DeadLetterFilter) (interface{}, Error) {
+	f := deadLetterFilter(filter)
+	if f.IsEmpty() {
+		return nil, NewServerError(InvalidParamsErrorCode, "at least one filter is required to purge dead-lettered txs")
+	}
+
+	purged, err := e.poolDB.PurgeDeadLetters(context.Background(), f)
+	if err != nil {
+		log.Errorf("error purging dead-lettered txs, error: %v", err)
+		return nil, NewServerError(DefaultErrorCode, "error purging dead-lettered txs")
+	}
+
+	log.Infof("%d dead-lettered txs purged", purged)
+	return purged, nil
+}
+
+// deadLetterFilter returns the filter, or an empty filter if it's not provided
+func deadLetterFilter(filter *types.DeadLetterFilter) types.DeadLetterFilter {
+	if filter == nil {
+		return types.DeadLetterFilter{}
+	}
+	return *filter
+}
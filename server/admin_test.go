@@ -0,0 +1,110 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/0xPolygonHermez/zkevm-pool-manager/types"
+	"github.com/jackc/pgx/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeAdminPoolDB is a dead-letter store that records the filters it receives
+type fakeAdminPoolDB struct {
+	deadLetters []*types.DeadLetter
+	filters     []types.DeadLetterFilter
+}
+
+func (p *fakeAdminPoolDB) GetDeadLetters(ctx context.Context, filter types.DeadLetterFilter) ([]*types.DeadLetter, error) {
+	p.filters = append(p.filters, filter)
+	return p.deadLetters, nil
+}
+
+func (p *fakeAdminPoolDB) GetDeadLetter(ctx context.Context, txID uint64) (*types.DeadLetter, error) {
+	for _, deadLetter := range p.deadLetters {
+		if deadLetter.TxId == txID {
+			return deadLetter, nil
+		}
+	}
+	return nil, pgx.ErrNoRows
+}
+
+func (p *fakeAdminPoolDB) RequeueDeadLetters(ctx context.Context, filter types.DeadLetterFilter) (int64, error) {
+	p.filters = append(p.filters, filter)
+	return int64(len(p.deadLetters)), nil
+}
+
+func (p *fakeAdminPoolDB) PurgeDeadLetters(ctx context.Context, filter types.DeadLetterFilter) (int64, error) {
+	p.filters = append(p.filters, filter)
+	return int64(len(p.deadLetters)), nil
+}
+
+func handleTestRequest(h *Handler, method string, params string) Response {
+	return h.Handle(handleRequest{Request: Request{JSONRPC: "2.0", ID: 1, Method: method, Params: json.RawMessage(params)}})
+}
+
+func TestAdminEndpoints(t *testing.T) {
+	poolDB := &fakeAdminPoolDB{deadLetters: []*types.DeadLetter{{TxId: 7, Category: types.DeadLetterCategoryNonce}}}
+
+	h := newJSONRpcHandler()
+	h.registerEndpoints(EthNamespace, NewEndpoints(NewMockConfig(), &poolDBMock{}, &senderMock{}))
+
+	// The admin endpoints are not available until they are registered
+	res := handleTestRequest(h, "admin_listDeadLetters", "[]")
+	require.NotNil(t, res.Error)
+	assert.Equal(t, NotFoundErrorCode, res.Error.Code)
+
+	h.registerEndpoints(AdminNamespace, NewAdminEndpoints(poolDB))
+
+	// The public endpoints are only available in their own namespace
+	res = handleTestRequest(h, "admin_sendRawTransaction", `["0x00"]`)
+	require.NotNil(t, res.Error)
+	assert.Equal(t, NotFoundErrorCode, res.Error.Code)
+
+	res = handleTestRequest(h, "admin_listDeadLetters", `[{"categories":["nonce"],"limit":10}]`)
+	require.Nil(t, res.Error)
+	assert.Equal(t, types.DeadLetterFilter{Categories: []string{"nonce"}, Limit: 10}, poolDB.filters[0])
+
+	res = handleTestRequest(h, "admin_getDeadLetter", "[7]")
+	require.Nil(t, res.Error)
+	res = handleTestRequest(h, "admin_getDeadLetter", "[8]")
+	require.NotNil(t, res.Error)
+	assert.Equal(t, "tx 8 is not dead-lettered", res.Error.Message)
+
+	// Requeuing or purging all the dead-lettered txs requires an explicit filter, the limit is not enough
+	res = handleTestRequest(h, "admin_requeueDeadLetters", "[]")
+	require.NotNil(t, res.Error)
+	assert.Equal(t, InvalidParamsErrorCode, res.Error.Code)
+	res = handleTestRequest(h, "admin_requeueDeadLetters", `[{"limit":10}]`)
+	require.NotNil(t, res.Error)
+	assert.Equal(t, InvalidParamsErrorCode, res.Error.Code)
+	res = handleTestRequest(h, "admin_purgeDeadLetters", "[]")
+	require.NotNil(t, res.Error)
+	assert.Equal(t, InvalidParamsErrorCode, res.Error.Code)
+	assert.Len(t, poolDB.filters, 1)
+
+	res = handleTestRequest(h, "admin_requeueDeadLetters", `[{"categories":["expired"]}]`)
+	require.Nil(t, res.Error)
+	assert.Equal(t, types.DeadLetterFilter{Categories: []string{"expired"}}, poolDB.filters[1])
+
+	res = handleTestRequest(h, "admin_purgeDeadLetters", `[{"txIds":[7]}]`)
+	require.Nil(t, res.Error)
+	assert.Equal(t, json.RawMessage("1"), res.Result)
+}
+
+func TestAdminEndpointsServedApart(t *testing.T) {
+	cfg := NewMockConfig()
+	cfg.AdminEnabled = true
+	s := NewServer(cfg, nil, &senderMock{}, nil, nil)
+
+	// The admin endpoints are not served by the public handler, and the public endpoints are not served by the admin one
+	res := handleTestRequest(s.handler, "admin_listDeadLetters", "[]")
+	require.NotNil(t, res.Error)
+	assert.Equal(t, NotFoundErrorCode, res.Error.Code)
+	require.NotNil(t, s.adminHandler)
+	res = handleTestRequest(s.adminHandler, "eth_sendRawTransaction", `["0x00"]`)
+	require.NotNil(t, res.Error)
+	assert.Equal(t, NotFoundErrorCode, res.Error.Code)
+}
@@ -27,4 +27,15 @@ type Config struct {
 
 	// BatchRequestsLimit defines the limit of requests that can be incorporated into each batch request
 	BatchRequestsLimit uint `mapstructure:"BatchRequestsLimit"`
+
+	// AdminEnabled enables the admin JSON-RPC endpoints to inspect, requeue and purge the dead-lettered txs. They are
+	// served on their own AdminHost and AdminPort, apart from the pool-manager endpoints
+	AdminEnabled bool `mapstructure:"AdminEnabled"`
+
+	// AdminHost defines the network adapter that will be used to serve the admin endpoints. Keep it on a private
+	// network adapter (e.g. localhost), as the admin endpoints are not authenticated
+	AdminHost string `mapstructure:"AdminHost"`
+
+	// AdminPort defines the port to serve the admin endpoints via HTTP
+	AdminPort int `mapstructure:"AdminPort"`
 }
@@ -13,6 +13,9 @@ import (
 	ethTypes "github.com/ethereum/go-ethereum/core/types"
 )
 
+// apiKeyHeader is the HTTP header used by the clients to send their API key
+const apiKeyHeader = "X-Api-Key"
+
 // Endpoints contains implementations for the pool-manager JSON-RPC endpoints
 type Endpoints struct {
 	cfg    Config
@@ -26,7 +29,7 @@ func NewEndpoints(cfg Config, poolDB poolDBInterface, sender senderInterface) *E
 	return e
 }
 
-func (e *Endpoints) SendRawTransaction(httpRequest *http.Request, input string) (interface{}, Error) {
+func (e *Endpoints) SendRawTransaction(httpRequest *http.Request, input string, options *SendRawTransactionOptions) (interface{}, Error) {
 	// Get the IP address of the request
 	ip := ""
 	ips := httpRequest.Header.Get("X-Forwarded-For")
@@ -34,12 +37,23 @@ func (e *Endpoints) SendRawTransaction(httpRequest *http.Request, input string)
 		ip = strings.Split(ips, ",")[0]
 	}
 
+	// Get the API key used by the client (if any)
+	apiKey := httpRequest.Header.Get(apiKeyHeader)
+
 	tx, err := hexToTx(input)
 	if err != nil {
 		log.Errorf("invalid tx input, error: %v", err)
 		return nil, NewServerErrorWithData(InvalidParamsErrorCode, "invalid tx input", nil)
 	}
 
+	var lifetime time.Duration
+	if options != nil && options.Lifetime != nil {
+		lifetime = options.Lifetime.Duration
+		if lifetime < time.Second {
+			return nil, NewServerErrorWithData(InvalidParamsErrorCode, "invalid tx lifetime, it must be at least 1s", nil)
+		}
+	}
+
 	txJSON, err := tx.MarshalJSON()
 	if err != nil {
 		log.Errorf("error getting JSON marshal for tx %s, error: %v", tx.Hash(), err)
@@ -65,6 +79,8 @@ func (e *Endpoints) SendRawTransaction(httpRequest *http.Request, input string)
 		IP:          ip,
 		Encoded:     input,
 		Decoded:     decoded,
+		APIKey:      apiKey,
+		Lifetime:    lifetime,
 	}
 
 	l2Tx.Id, err = e.poolDB.AddL2Transaction(context.Background(), l2Tx)
@@ -15,7 +15,15 @@ const (
 	requiredReturnParamsPerFn = 2
 )
 
+const (
+	// EthNamespace is the namespace of the public pool-manager endpoints
+	EthNamespace = "eth"
+	// AdminNamespace is the namespace of the admin endpoints
+	AdminNamespace = "admin"
+)
+
 type endpointData struct {
+	rcvr  reflect.Value
 	inNum int
 	reqt  []reflect.Type
 	fv    reflect.Value
@@ -33,7 +41,6 @@ type handleRequest struct {
 
 // Handler manage services to handle pool-manager RPC requests
 type Handler struct {
-	endpoints   reflect.Value
 	endpointMap map[string]*endpointData
 }
 
@@ -55,7 +62,7 @@ func (h *Handler) Handle(req handleRequest) Response {
 
 	inArgsOffset := 0
 	inArgs := make([]reflect.Value, fd.inNum)
-	inArgs[0] = h.endpoints
+	inArgs[0] = fd.rcvr
 
 	funcHasMoreThanOneInputParams := len(fd.reqt) > 1
 	firstFuncParamIsHttpRequest := false
@@ -103,13 +110,14 @@ func (h *Handler) Handle(req handleRequest) Response {
 	return NewResponse(req.Request, data, nil)
 }
 
-func (h *Handler) registerEndpoints(endpoints interface{}) {
+// registerEndpoints registers the exported methods of the endpoints as the functions of the namespace
+func (h *Handler) registerEndpoints(namespace string, endpoints interface{}) {
 	st := reflect.TypeOf(endpoints)
 	if st.Kind() == reflect.Struct {
 		panic("endpoints must be a pointer to struct")
 	}
 
-	funcMap := make(map[string]*endpointData)
+	rcvr := reflect.ValueOf(endpoints)
 	for i := 0; i < st.NumMethod(); i++ {
 		mv := st.Method(i)
 		if mv.PkgPath != "" {
@@ -118,9 +126,10 @@ func (h *Handler) registerEndpoints(endpoints interface{}) {
 		}
 
 		name := lowerCaseFirst(mv.Name)
-		funcName := "eth_" + name
+		funcName := namespace + "_" + name
 		fd := &endpointData{
-			fv: mv.Func,
+			rcvr: rcvr,
+			fv:   mv.Func,
 		}
 		var err error
 		if fd.inNum, fd.reqt, err = validateFunc(funcName, fd.fv, true); err != nil {
@@ -133,22 +142,18 @@ func (h *Handler) registerEndpoints(endpoints interface{}) {
 				fd.isDyn = true
 			}
 		}
-		funcMap[name] = fd
+		h.endpointMap[funcName] = fd
 	}
-
-	h.endpoints = reflect.ValueOf(endpoints)
-	h.endpointMap = funcMap
 }
 
 func (h *Handler) getFuncHandler(req Request) (*endpointData, Error) {
 	methodNotFoundErrorMessage := fmt.Sprintf("the function %s does not exist or is not available", req.Method)
 
-	_, funcName, found := strings.Cut(req.Method, "_")
-	if !found {
+	if !strings.Contains(req.Method, "_") {
 		return nil, NewServerError(NotFoundErrorCode, methodNotFoundErrorMessage)
 	}
 
-	fd, ok := h.endpointMap[funcName]
+	fd, ok := h.endpointMap[req.Method]
 	if !ok {
 		log.Debugf("function '%s' not found", req.Method)
 		return nil, NewServerError(NotFoundErrorCode, methodNotFoundErrorMessage)
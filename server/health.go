@@ -0,0 +1,45 @@
+package server
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/0xPolygonHermez/zkevm-pool-manager/leader"
+	"github.com/0xPolygonHermez/zkevm-pool-manager/log"
+	"github.com/0xPolygonHermez/zkevm-pool-manager/supervisor"
+)
+
+const healthPath = "/health"
+
+// HealthResponse is the status of the pool-manager instance returned by the health endpoint
+type HealthResponse struct {
+	Leader         leader.Status     `json:"leader"`
+	SenderWorkers  supervisor.Status `json:"senderWorkers"`
+	MonitorWorkers supervisor.Status `json:"monitorWorkers"`
+}
+
+// handleHealth returns the status of the pool-manager instance
+func (s *Server) handleHealth(w http.ResponseWriter, req *http.Request) {
+	if req.Method != http.MethodGet {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	response := HealthResponse{
+		Leader:         s.leader.Status(),
+		SenderWorkers:  s.sender.WorkersStatus(),
+		MonitorWorkers: s.monitor.WorkersStatus(),
+	}
+
+	respBytes, err := json.Marshal(response)
+	if err != nil {
+		handleError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", contentType)
+	_, err = w.Write(respBytes)
+	if err != nil {
+		log.Error(err)
+	}
+}
@@ -3,6 +3,8 @@ package server
 import (
 	"context"
 
+	"github.com/0xPolygonHermez/zkevm-pool-manager/leader"
+	"github.com/0xPolygonHermez/zkevm-pool-manager/supervisor"
 	"github.com/0xPolygonHermez/zkevm-pool-manager/types"
 )
 
@@ -11,6 +13,22 @@ type poolDBInterface interface {
 	UpdateL2TransactionStatus(ctx context.Context, id uint64, newStatus string, errorMsg string) error
 }
 
+type adminPoolDBInterface interface {
+	GetDeadLetters(ctx context.Context, filter types.DeadLetterFilter) ([]*types.DeadLetter, error)
+	GetDeadLetter(ctx context.Context, txID uint64) (*types.DeadLetter, error)
+	RequeueDeadLetters(ctx context.Context, filter types.DeadLetterFilter) (int64, error)
+	PurgeDeadLetters(ctx context.Context, filter types.DeadLetterFilter) (int64, error)
+}
+
 type senderInterface interface {
 	SendL2Transaction(l2Tx *types.L2Transaction) error
+	WorkersStatus() supervisor.Status
+}
+
+type monitorInterface interface {
+	WorkersStatus() supervisor.Status
+}
+
+type leaderInterface interface {
+	Status() leader.Status
 }
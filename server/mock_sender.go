@@ -3,6 +3,7 @@
 package server
 
 import (
+	supervisor "github.com/0xPolygonHermez/zkevm-pool-manager/supervisor"
 	types "github.com/0xPolygonHermez/zkevm-pool-manager/types"
 	mock "github.com/stretchr/testify/mock"
 )
@@ -30,6 +31,24 @@ func (_m *senderMock) SendL2Transaction(l2Tx *types.L2Transaction) error {
 	return r0
 }
 
+// WorkersStatus provides a mock function with given fields:
+func (_m *senderMock) WorkersStatus() supervisor.Status {
+	ret := _m.Called()
+
+	if len(ret) == 0 {
+		panic("no return value specified for WorkersStatus")
+	}
+
+	var r0 supervisor.Status
+	if rf, ok := ret.Get(0).(func() supervisor.Status); ok {
+		r0 = rf()
+	} else {
+		r0 = ret.Get(0).(supervisor.Status)
+	}
+
+	return r0
+}
+
 // newSenderMock creates a new instance of senderMock. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
 // The first argument is typically a *testing.T value.
 func newSenderMock(t interface {
@@ -32,69 +32,120 @@ type Server struct {
 	config     Config
 	handler    *Handler
 	httpServer *http.Server
-	sender     senderInterface
+	// adminHandler serves the admin endpoints on their own HTTP server, it's nil if they are not enabled
+	adminHandler    *Handler
+	adminHTTPServer *http.Server
+	sender          senderInterface
+	monitor         monitorInterface
+	leader          leaderInterface
 }
 
 // NewServer returns a JSON-RPC server to handle pool-manager requests
-func NewServer(cfg Config, poolDB *db.PoolDB, sender senderInterface) *Server {
+func NewServer(cfg Config, poolDB *db.PoolDB, sender senderInterface, monitor monitorInterface, leader leaderInterface) *Server {
 	endpoints := NewEndpoints(cfg, poolDB, sender)
 
 	handler := newJSONRpcHandler()
-	handler.registerEndpoints(endpoints)
+	handler.registerEndpoints(EthNamespace, endpoints)
 
-	return &Server{config: cfg, handler: handler, sender: sender}
+	var adminHandler *Handler
+	if cfg.AdminEnabled {
+		adminHandler = newJSONRpcHandler()
+		adminHandler.registerEndpoints(AdminNamespace, NewAdminEndpoints(poolDB))
+	}
+
+	return &Server{config: cfg, handler: handler, adminHandler: adminHandler, sender: sender, monitor: monitor, leader: leader}
 }
 
-// Start initializes pool-manager JSON-RPC server to listen for requests
+// Start initializes pool-manager JSON-RPC server to listen for requests. If the admin endpoints are enabled they are
+// served by another HTTP server on the AdminHost and AdminPort
 func (s *Server) Start() {
 	if s.httpServer != nil {
 		log.Fatalf("HTTP server already started")
 	}
 
-	address := fmt.Sprintf("%s:%d", s.config.Host, s.config.Port)
-
-	lis, err := net.Listen("tcp", address)
-	if err != nil {
-		log.Fatalf("failed to create TCP listener, error: %v", err)
+	if s.adminHandler != nil {
+		adminMux := http.NewServeMux()
+		adminMux.HandleFunc("/", s.handleAdmin)
+		s.adminHTTPServer = s.newHTTPServer(adminMux)
+		go s.serve("admin HTTP server", fmt.Sprintf("%s:%d", s.config.AdminHost, s.config.AdminPort), s.adminHTTPServer)
 	}
 
 	mux := http.NewServeMux()
 
 	lmt := tollbooth.NewLimiter(s.config.MaxRequestsPerIPAndSecond, nil)
 	mux.Handle("/", tollbooth.LimitFuncHandler(lmt, s.handle))
+	mux.HandleFunc(healthPath, s.handleHealth)
 
-	s.httpServer = &http.Server{
-		Handler:           mux,
+	s.httpServer = s.newHTTPServer(mux)
+	s.serve("HTTP server", fmt.Sprintf("%s:%d", s.config.Host, s.config.Port), s.httpServer)
+}
+
+// newHTTPServer returns a HTTP server with the timeouts of the config
+func (s *Server) newHTTPServer(handler http.Handler) *http.Server {
+	return &http.Server{
+		Handler:           handler,
 		ReadHeaderTimeout: s.config.ReadTimeout.Duration,
 		ReadTimeout:       s.config.ReadTimeout.Duration,
 		WriteTimeout:      s.config.WriteTimeout.Duration,
 	}
-	log.Infof("HTTP server started at %s", address)
-	if err := s.httpServer.Serve(lis); err != nil {
+}
+
+// serve listens on the address and serves the requests with the HTTP server until it's stopped
+func (s *Server) serve(name string, address string, httpServer *http.Server) {
+	lis, err := net.Listen("tcp", address)
+	if err != nil {
+		log.Fatalf("failed to create TCP listener, error: %v", err)
+	}
+
+	log.Infof("%s started at %s", name, address)
+	if err := httpServer.Serve(lis); err != nil {
 		if err == http.ErrServerClosed {
-			log.Fatalf("HTTP server stopped")
+			log.Infof("%s stopped", name)
+			return
 		}
 		log.Fatalf("closed HTTP connection, error: %v", err)
 	}
 }
 
 // Stop shutdown the JSON-RPC server
-func (s *Server) Stop() error {
+func (s *Server) Stop(ctx context.Context) error {
 	if s.httpServer != nil {
-		if err := s.httpServer.Shutdown(context.Background()); err != nil {
+		if err := stopHTTPServer(ctx, s.httpServer); err != nil {
 			return err
 		}
+		s.httpServer = nil
+	}
 
-		if err := s.httpServer.Close(); err != nil {
+	if s.adminHTTPServer != nil {
+		if err := stopHTTPServer(ctx, s.adminHTTPServer); err != nil {
 			return err
 		}
-		s.httpServer = nil
+		s.adminHTTPServer = nil
 	}
 
 	return nil
 }
 
+// stopHTTPServer shuts down the HTTP server, waiting for the requests in progress until the context is done
+func stopHTTPServer(ctx context.Context, httpServer *http.Server) error {
+	if err := httpServer.Shutdown(ctx); err != nil {
+		return err
+	}
+
+	return httpServer.Close()
+}
+
+// handle serves the requests to the pool-manager endpoints
 func (s *Server) handle(w http.ResponseWriter, req *http.Request) {
+	s.handleWith(s.handler, w, req)
+}
+
+// handleAdmin serves the requests to the admin endpoints
+func (s *Server) handleAdmin(w http.ResponseWriter, req *http.Request) {
+	s.handleWith(s.adminHandler, w, req)
+}
+
+func (s *Server) handleWith(handler *Handler, w http.ResponseWriter, req *http.Request) {
 	w.Header().Set("Content-Type", contentType)
 	w.Header().Set("Access-Control-Allow-Origin", "*")
 	w.Header().Set("Access-Control-Allow-Methods", "POST, OPTIONS")
@@ -133,9 +184,9 @@ func (s *Server) handle(w http.ResponseWriter, req *http.Request) {
 	start := time.Now()
 	var respLen int
 	if single {
-		respLen = s.handleSingleRequest(req, w, data)
+		respLen = s.handleSingleRequest(handler, req, w, data)
 	} else {
-		respLen = s.handleBatchRequest(req, w, data)
+		respLen = s.handleBatchRequest(handler, req, w, data)
 	}
 	s.combinedLog(req, start, http.StatusOK, respLen)
 }
@@ -176,14 +227,14 @@ func (s *Server) isSingleRequest(data []byte) (bool, error) {
 	return x[0] != '[', nil
 }
 
-func (s *Server) handleSingleRequest(httpRequest *http.Request, w http.ResponseWriter, data []byte) int {
+func (s *Server) handleSingleRequest(handler *Handler, httpRequest *http.Request, w http.ResponseWriter, data []byte) int {
 	request, err := s.parseRequest(data)
 	if err != nil {
 		handleInvalidRequest(w, err, http.StatusBadRequest)
 		return 0
 	}
 	req := handleRequest{Request: request, HttpRequest: httpRequest}
-	response := s.handler.Handle(req)
+	response := handler.Handle(req)
 
 	respBytes, err := json.Marshal(response)
 	if err != nil {
@@ -199,7 +250,7 @@ func (s *Server) handleSingleRequest(httpRequest *http.Request, w http.ResponseW
 	return len(respBytes)
 }
 
-func (s *Server) handleBatchRequest(httpRequest *http.Request, w http.ResponseWriter, data []byte) int {
+func (s *Server) handleBatchRequest(handler *Handler, httpRequest *http.Request, w http.ResponseWriter, data []byte) int {
 	// Checking if batch requests are enabled
 	if !s.config.BatchRequestsEnabled {
 		handleInvalidRequest(w, ErrBatchRequestsDisabled, http.StatusBadRequest)
@@ -224,7 +275,7 @@ func (s *Server) handleBatchRequest(httpRequest *http.Request, w http.ResponseWr
 
 	for _, request := range requests {
 		req := handleRequest{Request: request, HttpRequest: httpRequest}
-		response := s.handler.Handle(req)
+		response := handler.Handle(req)
 		responses = append(responses, response)
 	}
 
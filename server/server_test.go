@@ -92,7 +92,7 @@ func TestSendRawTransaction(t *testing.T) {
 			tc.Prepare(&tc)
 			tc.SetupMocks()
 
-			_, err := endpoints.SendRawTransaction(nil, tc.RawTx)
+			_, err := endpoints.SendRawTransaction(nil, tc.RawTx, nil)
 			assert.Equal(t, tc.ExpectedError, err)
 		})
 	}
@@ -1,6 +1,10 @@
 package server
 
-import "encoding/json"
+import (
+	"encoding/json"
+
+	configTypes "github.com/0xPolygonHermez/zkevm-pool-manager/config/types"
+)
 
 // Request is a jsonrpc Request
 type Request struct {
@@ -25,6 +29,13 @@ type ErrorObject struct {
 	Data    *ArgBytes `json:"data,omitempty"`
 }
 
+// SendRawTransactionOptions are the optional settings of a tx, sent as the second param of eth_sendRawTransaction
+type SendRawTransactionOptions struct {
+	// Lifetime is the time the tx can wait for the receipt before the expiry policy is applied, up to the
+	// Monitor.TxLifeTimeMax (e.g. "5m")
+	Lifetime *configTypes.Duration `json:"lifetime,omitempty"`
+}
+
 // ArgBytes helps to marshal byte array values provided in the RPC requests
 type ArgBytes []byte
 
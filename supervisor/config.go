@@ -0,0 +1,17 @@
+package supervisor
+
+import "github.com/0xPolygonHermez/zkevm-pool-manager/config/types"
+
+// Config for the supervision of the workers
+type Config struct {
+	// RestartInitialBackoff is the time the supervisor waits before restarting a failed worker for first time
+	RestartInitialBackoff types.Duration `mapstructure:"RestartInitialBackoff"`
+
+	// RestartMaxBackoff is the max time the supervisor waits before restarting a failed worker. The wait time is doubled
+	// after each consecutive failure up to this value
+	RestartMaxBackoff types.Duration `mapstructure:"RestartMaxBackoff"`
+
+	// StartupTimeout is the max time to wait at startup for at least one worker to be alive. If no worker is alive
+	// after this time the pool-manager exits
+	StartupTimeout types.Duration `mapstructure:"StartupTimeout"`
+}
@@ -0,0 +1,160 @@
+package supervisor
+
+import (
+	"context"
+	"fmt"
+	"runtime/debug"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/0xPolygonHermez/zkevm-pool-manager/log"
+)
+
+// waitCheckInterval is the time between checks of the running workers when waiting for them to finish
+const waitCheckInterval = 10 * time.Millisecond
+
+// WorkerFunc runs a worker. It must call ready once the worker is connected and processing requests, and return when
+// the context is done. If it returns an error the worker is restarted, if it returns nil the worker has finished and
+// it's not restarted
+type WorkerFunc func(ctx context.Context, workerNum int, ready func()) error
+
+// Supervisor runs a group of workers, restarting them with backoff if they fail or panic
+type Supervisor struct {
+	name     string
+	cfg      Config
+	workers  atomic.Int32
+	alive    atomic.Int32
+	restarts atomic.Uint64
+	cond     *sync.Cond
+}
+
+// Status is the status of the workers of a supervisor
+type Status struct {
+	Workers  int    `json:"workers"`
+	Alive    int    `json:"alive"`
+	Restarts uint64 `json:"restarts"`
+}
+
+// NewSupervisor creates a new supervisor for a group of workers
+func NewSupervisor(name string, cfg Config) *Supervisor {
+	return &Supervisor{
+		name: name,
+		cfg:  cfg,
+		cond: sync.NewCond(&sync.Mutex{}),
+	}
+}
+
+// Go starts a supervised worker in a new goroutine. The worker is not restarted once the context is done
+func (s *Supervisor) Go(ctx context.Context, workerNum int, worker WorkerFunc) {
+	s.workers.Add(1)
+	go s.supervise(ctx, workerNum, worker)
+}
+
+// Wait waits until all the workers have finished or the context is done
+func (s *Supervisor) Wait(ctx context.Context) error {
+	ticker := time.NewTicker(waitCheckInterval)
+	defer ticker.Stop()
+
+	for s.workers.Load() > 0 {
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("%d %s workers still running, error: %w", s.workers.Load(), s.name, ctx.Err())
+		case <-ticker.C:
+		}
+	}
+
+	return nil
+}
+
+// Status returns the status of the workers
+func (s *Supervisor) Status() Status {
+	return Status{
+		Workers:  int(s.workers.Load()),
+		Alive:    int(s.alive.Load()),
+		Restarts: s.restarts.Load(),
+	}
+}
+
+// WaitAlive waits until at least one worker is alive. Returns an error if no worker is alive after the StartupTimeout
+func (s *Supervisor) WaitAlive() error {
+	timer := time.AfterFunc(s.cfg.StartupTimeout.Duration, func() {
+		s.cond.L.Lock()
+		defer s.cond.L.Unlock()
+		s.cond.Broadcast()
+	})
+	defer timer.Stop()
+
+	deadline := time.Now().Add(s.cfg.StartupTimeout.Duration)
+
+	s.cond.L.Lock()
+	defer s.cond.L.Unlock()
+
+	for s.alive.Load() == 0 {
+		if !time.Now().Before(deadline) {
+			return fmt.Errorf("no %s worker is alive after %v", s.name, s.cfg.StartupTimeout.Duration)
+		}
+		s.cond.Wait()
+	}
+
+	return nil
+}
+
+// supervise runs the worker, restarting it with backoff until it finishes without error or the context is done
+func (s *Supervisor) supervise(ctx context.Context, workerNum int, worker WorkerFunc) {
+	defer s.workers.Add(-1)
+
+	backoff := s.cfg.RestartInitialBackoff.Duration
+	for {
+		start := time.Now()
+		err := s.run(ctx, workerNum, worker)
+		if err == nil || ctx.Err() != nil {
+			log.Debugf("%s-worker[%03d]: finished", s.name, workerNum)
+			return
+		}
+
+		// Reset the backoff if the worker was running for a while before failing
+		if time.Since(start) > s.cfg.RestartMaxBackoff.Duration {
+			backoff = s.cfg.RestartInitialBackoff.Duration
+		}
+
+		log.Errorf("%s-worker[%03d]: failed, restarting in %v, error: %v", s.name, workerNum, backoff, err)
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(backoff):
+		}
+		s.restarts.Add(1)
+
+		backoff *= 2
+		if backoff > s.cfg.RestartMaxBackoff.Duration {
+			backoff = s.cfg.RestartMaxBackoff.Duration
+		}
+	}
+}
+
+// run runs the worker once, recovering from panics
+func (s *Supervisor) run(ctx context.Context, workerNum int, worker WorkerFunc) (err error) {
+	isAlive := false
+	ready := func() {
+		if isAlive {
+			return
+		}
+		isAlive = true
+		s.cond.L.Lock()
+		defer s.cond.L.Unlock()
+		s.alive.Add(1)
+		s.cond.Broadcast()
+	}
+
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("panic: %v\n%s", r, debug.Stack())
+		}
+		if isAlive {
+			s.alive.Add(-1)
+		}
+	}()
+
+	return worker(ctx, workerNum, ready)
+}
@@ -0,0 +1,70 @@
+package supervisor
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/0xPolygonHermez/zkevm-pool-manager/config/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestConfig() Config {
+	return Config{
+		RestartInitialBackoff: types.NewDuration(time.Millisecond),
+		RestartMaxBackoff:     types.NewDuration(10 * time.Millisecond),
+		StartupTimeout:        types.NewDuration(time.Second),
+	}
+}
+
+func TestSupervisorRestartsFailedWorkers(t *testing.T) {
+	s := NewSupervisor("test", newTestConfig())
+
+	var runs atomic.Int32
+	ctx, cancel := context.WithCancel(context.Background())
+	s.Go(ctx, 0, func(ctx context.Context, workerNum int, ready func()) error {
+		switch runs.Add(1) {
+		case 1:
+			return errors.New("connection refused")
+		case 2:
+			panic("worker panic")
+		}
+		ready()
+		<-ctx.Done()
+		return nil
+	})
+
+	require.NoError(t, s.WaitAlive())
+
+	status := s.Status()
+	assert.Equal(t, Status{Workers: 1, Alive: 1, Restarts: 2}, status)
+
+	cancel()
+	waitCtx, waitCancel := context.WithTimeout(context.Background(), time.Second)
+	defer waitCancel()
+	require.NoError(t, s.Wait(waitCtx))
+	assert.Equal(t, 0, s.Status().Workers)
+	assert.Equal(t, 0, s.Status().Alive)
+	assert.Equal(t, int32(3), runs.Load())
+}
+
+func TestSupervisorWaitAliveTimeout(t *testing.T) {
+	cfg := newTestConfig()
+	cfg.StartupTimeout = types.NewDuration(50 * time.Millisecond)
+	s := NewSupervisor("test", cfg)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	s.Go(ctx, 0, func(ctx context.Context, workerNum int, ready func()) error {
+		return errors.New("connection refused")
+	})
+
+	start := time.Now()
+	err := s.WaitAlive()
+	require.Error(t, err)
+	assert.GreaterOrEqual(t, time.Since(start), cfg.StartupTimeout.Duration)
+	assert.Equal(t, 0, s.Status().Alive)
+}
@@ -0,0 +1,108 @@
+package types
+
+import (
+	"strings"
+	"time"
+)
+
+const (
+	// DeadLetterCategoryNonce is the category of the txs rejected because of its nonce
+	DeadLetterCategoryNonce = "nonce"
+	// DeadLetterCategoryFunds is the category of the txs rejected because the sender has not enough funds
+	DeadLetterCategoryFunds = "funds"
+	// DeadLetterCategoryGas is the category of the txs rejected because of its gas limit or gas price
+	DeadLetterCategoryGas = "gas"
+	// DeadLetterCategoryRejected is the category of the txs rejected by the sequencer for other reasons
+	DeadLetterCategoryRejected = "rejected"
+	// DeadLetterCategoryReverted is the category of the txs mined with a failed receipt
+	DeadLetterCategoryReverted = "reverted"
+	// DeadLetterCategoryExpired is the category of the txs without receipt after the max monitoring time
+	DeadLetterCategoryExpired = "expired"
+	// DeadLetterCategorySendLimit is the category of the txs dropped after reaching the send limits
+	DeadLetterCategorySendLimit = "send_limit"
+)
+
+const (
+	// TxErrorSourceSender is the source of the errors returned by the sequencer when sending the tx
+	TxErrorSourceSender = "sender"
+	// TxErrorSourceStatus is the source of the errors set with a status change of the tx
+	TxErrorSourceStatus = "status"
+	// TxErrorSourceAdmin is the source of the actions done by the admin on the tx
+	TxErrorSourceAdmin = "admin"
+)
+
+// DeadLetter is a tx that has reached a failure status, with its failure context
+type DeadLetter struct {
+	TxId         uint64                `json:"txId"`
+	Hash         string                `json:"hash"`
+	FromAddress  string                `json:"fromAddress"`
+	Nonce        uint64                `json:"nonce"`
+	Status       string                `json:"status"`
+	Category     string                `json:"category"`
+	Error        string                `json:"error"`
+	ReceivedAt   time.Time             `json:"receivedAt"`
+	CreatedAt    time.Time             `json:"createdAt"`
+	AttemptCount uint64                `json:"attemptCount"`
+	FirstSentAt  time.Time             `json:"firstSentAt,omitempty"`
+	LastSentAt   time.Time             `json:"lastSentAt,omitempty"`
+	RequeueCount uint64                `json:"requeueCount"`
+	Encoded      string                `json:"encoded,omitempty"`
+	Errors       []*L2TransactionError `json:"errors,omitempty"`
+}
+
+// L2TransactionError is an error in the history of a tx
+type L2TransactionError struct {
+	CreatedAt time.Time `json:"createdAt"`
+	Source    string    `json:"source"`
+	Status    string    `json:"status,omitempty"`
+	Error     string    `json:"error"`
+}
+
+// DeadLetterFilter selects the dead-lettered txs. Empty fields are not used to filter
+type DeadLetterFilter struct {
+	TxIds      []uint64  `json:"txIds,omitempty"`
+	Categories []string  `json:"categories,omitempty"`
+	From       time.Time `json:"from,omitempty"`
+	To         time.Time `json:"to,omitempty"`
+	Limit      uint64    `json:"limit,omitempty"`
+}
+
+// IsEmpty returns true if the filter doesn't select the dead-lettered txs by id, category or time, so it selects all
+// of them. The limit is not a filter, as it doesn't restrict which txs are selected
+func (f DeadLetterFilter) IsEmpty() bool {
+	return len(f.TxIds) == 0 && len(f.Categories) == 0 && f.From.IsZero() && f.To.IsZero()
+}
+
+// IsDeadLetterStatus returns true if the txs with the status are moved to the dead-letter store
+func IsDeadLetterStatus(status string) bool {
+	switch status {
+	case TxStatusInvalid, TxStatusFailed, TxStatusExpired, TxStatusDropped:
+		return true
+	default:
+		return false
+	}
+}
+
+// DeadLetterCategory returns the category of a tx that reached the status with the error
+func DeadLetterCategory(status string, errorMsg string) string {
+	switch status {
+	case TxStatusFailed:
+		return DeadLetterCategoryReverted
+	case TxStatusExpired:
+		return DeadLetterCategoryExpired
+	case TxStatusDropped:
+		return DeadLetterCategorySendLimit
+	}
+
+	errorMsg = strings.ToLower(errorMsg)
+	switch {
+	case strings.Contains(errorMsg, "nonce"):
+		return DeadLetterCategoryNonce
+	case strings.Contains(errorMsg, "insufficient funds"):
+		return DeadLetterCategoryFunds
+	case strings.Contains(errorMsg, "gas"), strings.Contains(errorMsg, "underpriced"):
+		return DeadLetterCategoryGas
+	default:
+		return DeadLetterCategoryRejected
+	}
+}
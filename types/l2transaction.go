@@ -20,6 +20,15 @@ const (
 	TxStatusResend string = "resend"
 	// TxStatusResend represents a tx that has reached TxLifeTimeMax time wating to receive the receipt
 	TxStatusExpired string = "expired"
+	// TxStatusDropped represents a tx that has reached the max number of send attempts or the max resend time
+	TxStatusDropped string = "dropped"
+	// TxStatusVirtualized represents a confirmed tx whose L2 batch has been virtualized (sequenced) on L1
+	TxStatusVirtualized string = "virtualized"
+	// TxStatusVerified represents a confirmed tx whose L2 batch has been verified on L1, so the tx is final
+	TxStatusVerified string = "verified"
+	// TxStatusReplaced represents a sent tx that was dropped by the sequencer, as another tx with the same nonce of the
+	// account was included in a L2 block
+	TxStatusReplaced string = "replaced"
 )
 
 // L2Transaction represents a L2 transaction
@@ -34,8 +43,54 @@ type L2Transaction struct {
 	IP          string
 	Encoded     string
 	Decoded     string
+	// AttemptCount is the number of times the tx has been sent to the sequencer
+	AttemptCount uint64
+	// FirstSentAt is the time of the first attempt to send the tx to the sequencer
+	FirstSentAt time.Time
+	// LastSentAt is the time of the last attempt to send the tx to the sequencer
+	LastSentAt time.Time
+	// LastError is the error returned by the last attempt to send the tx to the sequencer
+	LastError string
+	// Lifetime is the time the tx can wait for the receipt, set by the client when the tx is sent. If it's 0 the
+	// Monitor.TxLifeTimeMax is used
+	Lifetime time.Duration
+	// ExpiryResends is the number of times the tx has been resent because it expired waiting for the receipt
+	ExpiryResends uint64
+	// NextCheckAt is the time the monitor will check the receipt of the tx again, persisted to restore the monitor
+	// schedule after a restart
+	NextCheckAt time.Time
+	// CheckCount is the number of times the monitor has checked the receipt of the tx
+	CheckCount uint64
+	// APIKey is the key used by the client to send the tx, stored in the pool database so the txs read from it are
+	// queued with the same client key by the fair queue
+	APIKey string
+	// RequeuedAt is the time the tx was last requeued from the dead-letter store
+	RequeuedAt time.Time
+}
+
+// L2TransactionCheck is the schedule of the next check of the receipt of a monitored tx
+type L2TransactionCheck struct {
+	TxId        uint64
+	NextCheckAt time.Time
+	CheckCount  uint64
+}
+
+// L2TransactionNotification is the notification sent by the pool database when a tx changes to pending or resend status
+type L2TransactionNotification struct {
+	Id      uint64 `json:"id"`
+	Status  string `json:"status"`
+	OwnerID string `json:"ownerId"`
 }
 
 func (t *L2Transaction) Tag() string {
 	return fmt.Sprintf("[%d]:%s", t.Id, t.Hash)
 }
+
+// QueuedAt returns the time the tx was last queued to be sent: the time it was requeued from the dead-letter store, or
+// the time it was received if it has not been requeued
+func (t *L2Transaction) QueuedAt() time.Time {
+	if !t.RequeuedAt.IsZero() {
+		return t.RequeuedAt
+	}
+	return t.ReceivedAt
+}
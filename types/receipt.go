@@ -0,0 +1,28 @@
+package types
+
+import (
+	"math/big"
+	"time"
+)
+
+// L2TransactionReceipt represents the details of the receipt of a L2 transaction stored in the pool database
+type L2TransactionReceipt struct {
+	TxId      uint64    `json:"txId"`
+	CreatedAt time.Time `json:"createdAt"`
+	// Status is the status of the receipt, 1 for success and 0 for failure
+	Status            uint64   `json:"status"`
+	BlockNumber       uint64   `json:"blockNumber"`
+	BlockHash         string   `json:"blockHash"`
+	TxIndex           uint64   `json:"transactionIndex"`
+	GasUsed           uint64   `json:"gasUsed"`
+	EffectiveGasPrice *big.Int `json:"effectiveGasPrice,omitempty"`
+	// ContractAddress is the address of the contract created by the tx, empty if the tx is not a contract creation
+	ContractAddress string `json:"contractAddress,omitempty"`
+	LogsBloom       []byte `json:"logsBloom"`
+}
+
+// L2Block represents a L2 block that includes txs of the pool
+type L2Block struct {
+	Number uint64
+	Hash   string
+}
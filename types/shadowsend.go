@@ -0,0 +1,20 @@
+package types
+
+import "time"
+
+// ShadowSendResult is the result of sending a tx to the primary sequencer and to the shadow sequencer
+type ShadowSendResult struct {
+	TxId           uint64
+	Hash           string
+	SentAt         time.Time
+	PrimaryError   string
+	PrimaryLatency time.Duration
+	ShadowURL      string
+	ShadowError    string
+	ShadowLatency  time.Duration
+}
+
+// Match returns true if the primary and the shadow sequencer returned the same result
+func (r *ShadowSendResult) Match() bool {
+	return r.PrimaryError == r.ShadowError
+}